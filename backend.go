@@ -0,0 +1,56 @@
+package rateLimiter
+
+import (
+	"context"
+	"time"
+)
+
+//Backend is the storage and scripting surface RateLimiter needs. It exists
+//so RateLimiter doesn't depend on the concrete go-tron/redis client: tests
+//and single-node deploys can run against InMemoryBackend, multi-node
+//deploys against RedisBackend/RedisClusterBackend, and hot paths against
+//MultiBackend.
+type Backend interface {
+	//Incr increments the integer value stored at key by one and returns the
+	//value after the increment.
+	Incr(ctx context.Context, key string) (int64, error)
+	//Expire sets a TTL on key, returning whether key existed.
+	Expire(ctx context.Context, key string, ttl time.Duration) (bool, error)
+	//Del removes one or more keys.
+	Del(ctx context.Context, keys ...string) (int64, error)
+	//SAdd adds a member to the set at key.
+	SAdd(ctx context.Context, key string, member string) (int64, error)
+	//SRem removes a member from the set at key.
+	SRem(ctx context.Context, key string, member string) (int64, error)
+	//SMembers returns all members of the set at key.
+	SMembers(ctx context.Context, key string) ([]string, error)
+	//FrequencyLimit increments the counter at key, arming a TTL of duration
+	//on its first increment, and returns the count after incrementing.
+	FrequencyLimit(ctx context.Context, key string, min int, duration time.Duration) (int, error)
+	//TTL returns the time remaining until key expires, or zero if key has no
+	//TTL set or doesn't exist. Check uses this to report an accurate
+	//Result.ResetAt for the fixed-window strategy instead of a fresh
+	//"now + Duration" estimate that drifts the longer a window stays open.
+	TTL(ctx context.Context, key string) (time.Duration, error)
+	//EvalScript runs a Lua script atomically and returns its raw result for
+	//the caller to type-assert. Backends that cannot execute arbitrary Lua
+	//(e.g. InMemoryBackend) only support the scripts this package ships.
+	EvalScript(ctx context.Context, script string, keys []string, args ...interface{}) (interface{}, error)
+	//ZAdd adds member to the sorted set at key with the given score.
+	ZAdd(ctx context.Context, key string, member string, score float64) (int64, error)
+	//ZScore returns the score of member in the sorted set at key, and
+	//whether member is present at all.
+	ZScore(ctx context.Context, key string, member string) (float64, bool, error)
+	//ZRemRangeByScore removes members of the sorted set at key with a score
+	//between min and max (inclusive), returning the number removed.
+	ZRemRangeByScore(ctx context.Context, key string, min, max float64) (int64, error)
+	//ZMembers returns every member of the sorted set at key along with its
+	//score, for hydrating an in-memory cache at startup.
+	ZMembers(ctx context.Context, key string) ([]ZMember, error)
+}
+
+//ZMember is one member of a Redis sorted set together with its score.
+type ZMember struct {
+	Member string
+	Score  float64
+}
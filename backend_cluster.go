@@ -0,0 +1,98 @@
+package rateLimiter
+
+import (
+	"context"
+	"github.com/go-tron/redis/script"
+	goredis "github.com/redis/go-redis/v9"
+	"time"
+)
+
+//RedisClusterBackend adapts *goredis.ClusterClient to Backend for deployments
+//sharded across a Redis Cluster. go-tron/redis only wraps a single-node
+//*redis.Client, so cluster mode talks to the underlying go-redis client
+//directly; FrequencyLimit reuses the same Lua script go-tron/redis runs
+//internally so both backends stay behaviorally identical.
+type RedisClusterBackend struct {
+	Store *goredis.ClusterClient
+}
+
+func NewRedisClusterBackend(store *goredis.ClusterClient) *RedisClusterBackend {
+	return &RedisClusterBackend{Store: store}
+}
+
+func (b *RedisClusterBackend) Incr(ctx context.Context, key string) (int64, error) {
+	return b.Store.Incr(ctx, key).Result()
+}
+
+func (b *RedisClusterBackend) Expire(ctx context.Context, key string, ttl time.Duration) (bool, error) {
+	return b.Store.Expire(ctx, key, ttl).Result()
+}
+
+func (b *RedisClusterBackend) Del(ctx context.Context, keys ...string) (int64, error) {
+	return b.Store.Del(ctx, keys...).Result()
+}
+
+func (b *RedisClusterBackend) SAdd(ctx context.Context, key string, member string) (int64, error) {
+	return b.Store.SAdd(ctx, key, member).Result()
+}
+
+func (b *RedisClusterBackend) SRem(ctx context.Context, key string, member string) (int64, error) {
+	return b.Store.SRem(ctx, key, member).Result()
+}
+
+func (b *RedisClusterBackend) SMembers(ctx context.Context, key string) ([]string, error) {
+	return b.Store.SMembers(ctx, key).Result()
+}
+
+func (b *RedisClusterBackend) FrequencyLimit(ctx context.Context, key string, min int, duration time.Duration) (int, error) {
+	return script.FrequencyLimit.Run(ctx, b.Store, []string{key}, min, int(duration/time.Second)).Int()
+}
+
+func (b *RedisClusterBackend) TTL(ctx context.Context, key string) (time.Duration, error) {
+	ttl, err := b.Store.PTTL(ctx, key).Result()
+	if err != nil {
+		return 0, err
+	}
+	if ttl < 0 {
+		return 0, nil
+	}
+	return ttl, nil
+}
+
+func (b *RedisClusterBackend) EvalScript(ctx context.Context, luaScript string, keys []string, args ...interface{}) (interface{}, error) {
+	//Lua scripts that touch multiple keys must hash to the same slot; callers
+	//evaluating the strategies in this package are safe since each script
+	//only ever operates on a single key.
+	return b.Store.Eval(ctx, luaScript, keys, args...).Result()
+}
+
+func (b *RedisClusterBackend) ZAdd(ctx context.Context, key string, member string, score float64) (int64, error) {
+	return b.Store.ZAdd(ctx, key, goredis.Z{Score: score, Member: member}).Result()
+}
+
+func (b *RedisClusterBackend) ZScore(ctx context.Context, key string, member string) (float64, bool, error) {
+	score, err := b.Store.ZScore(ctx, key, member).Result()
+	if err != nil {
+		if err == goredis.Nil {
+			return 0, false, nil
+		}
+		return 0, false, err
+	}
+	return score, true, nil
+}
+
+func (b *RedisClusterBackend) ZRemRangeByScore(ctx context.Context, key string, min, max float64) (int64, error) {
+	return b.Store.ZRemRangeByScore(ctx, key, formatScore(min), formatScore(max)).Result()
+}
+
+func (b *RedisClusterBackend) ZMembers(ctx context.Context, key string) ([]ZMember, error) {
+	result, err := b.Store.ZRangeWithScores(ctx, key, 0, -1).Result()
+	if err != nil {
+		return nil, err
+	}
+	members := make([]ZMember, 0, len(result))
+	for _, z := range result {
+		members = append(members, ZMember{Member: z.Member.(string), Score: z.Score})
+	}
+	return members, nil
+}
@@ -0,0 +1,434 @@
+package rateLimiter
+
+import (
+	"context"
+	"errors"
+	"math"
+	"sync"
+	"time"
+)
+
+//ErrScriptNotSupported is returned by InMemoryBackend.EvalScript for any
+//script other than the ones this package ships (token bucket, leaky bucket,
+//sliding-window-log), since there is no embedded Lua interpreter to run
+//arbitrary scripts against the in-memory store.
+var ErrScriptNotSupported = errors.New("rateLimiter: script not supported by InMemoryBackend")
+
+type memoryCounter struct {
+	count     int64
+	expiresAt time.Time
+}
+
+type memorySet struct {
+	members   map[string]struct{}
+	expiresAt time.Time
+}
+
+type memoryBucket struct {
+	level     float64
+	ts        int64
+	expiresAt time.Time
+}
+
+type memoryLog struct {
+	entries   []int64
+	expiresAt time.Time
+}
+
+type memoryZSet struct {
+	scores map[string]float64
+}
+
+//InMemoryBackend is a Backend implementation with no external dependencies,
+//for unit tests and single-node deploys that don't have Redis available. A
+//background goroutine sweeps expired counters/sets/buckets/logs on
+//SweepInterval so idle strategy state doesn't accumulate forever; call
+//Close to stop it.
+type InMemoryBackend struct {
+	SweepInterval time.Duration
+
+	mu       sync.Mutex
+	counters map[string]*memoryCounter
+	sets     map[string]*memorySet
+	buckets  map[string]*memoryBucket
+	logs     map[string]*memoryLog
+	zsets    map[string]*memoryZSet
+
+	stop chan struct{}
+	once sync.Once
+}
+
+func NewInMemoryBackend() *InMemoryBackend {
+	b := &InMemoryBackend{
+		SweepInterval: time.Minute,
+		counters:      make(map[string]*memoryCounter),
+		sets:          make(map[string]*memorySet),
+		buckets:       make(map[string]*memoryBucket),
+		logs:          make(map[string]*memoryLog),
+		zsets:         make(map[string]*memoryZSet),
+		stop:          make(chan struct{}),
+	}
+	go b.sweep()
+	return b
+}
+
+//Close stops the background TTL sweeper. It is safe to call more than once.
+func (b *InMemoryBackend) Close() {
+	b.once.Do(func() {
+		close(b.stop)
+	})
+}
+
+func (b *InMemoryBackend) sweep() {
+	ticker := time.NewTicker(b.SweepInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-b.stop:
+			return
+		case now := <-ticker.C:
+			b.mu.Lock()
+			for key, c := range b.counters {
+				if !c.expiresAt.IsZero() && now.After(c.expiresAt) {
+					delete(b.counters, key)
+				}
+			}
+			for key, s := range b.sets {
+				if !s.expiresAt.IsZero() && now.After(s.expiresAt) {
+					delete(b.sets, key)
+				}
+			}
+			for key, bucket := range b.buckets {
+				if !bucket.expiresAt.IsZero() && now.After(bucket.expiresAt) {
+					delete(b.buckets, key)
+				}
+			}
+			for key, l := range b.logs {
+				if !l.expiresAt.IsZero() && now.After(l.expiresAt) {
+					delete(b.logs, key)
+				}
+			}
+			b.mu.Unlock()
+		}
+	}
+}
+
+func (b *InMemoryBackend) Incr(ctx context.Context, key string) (int64, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	c, ok := b.counters[key]
+	if !ok || (!c.expiresAt.IsZero() && time.Now().After(c.expiresAt)) {
+		c = &memoryCounter{}
+		b.counters[key] = c
+	}
+	c.count++
+	return c.count, nil
+}
+
+func (b *InMemoryBackend) TTL(ctx context.Context, key string) (time.Duration, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	c, ok := b.counters[key]
+	if !ok || c.expiresAt.IsZero() {
+		return 0, nil
+	}
+	if remaining := time.Until(c.expiresAt); remaining > 0 {
+		return remaining, nil
+	}
+	return 0, nil
+}
+
+func (b *InMemoryBackend) Expire(ctx context.Context, key string, ttl time.Duration) (bool, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if c, ok := b.counters[key]; ok {
+		c.expiresAt = time.Now().Add(ttl)
+		return true, nil
+	}
+	if s, ok := b.sets[key]; ok {
+		s.expiresAt = time.Now().Add(ttl)
+		return true, nil
+	}
+	return false, nil
+}
+
+func (b *InMemoryBackend) Del(ctx context.Context, keys ...string) (int64, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	var deleted int64
+	for _, key := range keys {
+		if _, ok := b.counters[key]; ok {
+			delete(b.counters, key)
+			deleted++
+		}
+		if _, ok := b.sets[key]; ok {
+			delete(b.sets, key)
+			deleted++
+		}
+		if _, ok := b.buckets[key]; ok {
+			delete(b.buckets, key)
+			deleted++
+		}
+		if _, ok := b.logs[key]; ok {
+			delete(b.logs, key)
+			deleted++
+		}
+		if _, ok := b.zsets[key]; ok {
+			delete(b.zsets, key)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
+func (b *InMemoryBackend) SAdd(ctx context.Context, key string, member string) (int64, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	s, ok := b.sets[key]
+	if !ok {
+		s = &memorySet{members: make(map[string]struct{})}
+		b.sets[key] = s
+	}
+	if _, exists := s.members[member]; exists {
+		return 0, nil
+	}
+	s.members[member] = struct{}{}
+	return 1, nil
+}
+
+func (b *InMemoryBackend) SRem(ctx context.Context, key string, member string) (int64, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	s, ok := b.sets[key]
+	if !ok {
+		return 0, nil
+	}
+	if _, exists := s.members[member]; !exists {
+		return 0, nil
+	}
+	delete(s.members, member)
+	return 1, nil
+}
+
+func (b *InMemoryBackend) SMembers(ctx context.Context, key string) ([]string, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	s, ok := b.sets[key]
+	if !ok {
+		return nil, nil
+	}
+	members := make([]string, 0, len(s.members))
+	for m := range s.members {
+		members = append(members, m)
+	}
+	return members, nil
+}
+
+func (b *InMemoryBackend) FrequencyLimit(ctx context.Context, key string, min int, duration time.Duration) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	c, ok := b.counters[key]
+	if !ok || time.Now().After(c.expiresAt) {
+		c = &memoryCounter{count: int64(min), expiresAt: time.Now().Add(duration)}
+		b.counters[key] = c
+	}
+	c.count++
+	return int(c.count), nil
+}
+
+func (b *InMemoryBackend) EvalScript(ctx context.Context, script string, keys []string, args ...interface{}) (interface{}, error) {
+	switch script {
+	case tokenBucketScript:
+		return b.evalTokenBucket(keys[0], args)
+	case leakyBucketScript:
+		return b.evalLeakyBucket(keys[0], args)
+	case slidingWindowLogScript:
+		return b.evalSlidingWindowLog(keys[0], args)
+	default:
+		return nil, ErrScriptNotSupported
+	}
+}
+
+func (b *InMemoryBackend) evalTokenBucket(key string, args []interface{}) (interface{}, error) {
+	capacity := toFloat(args[0])
+	rate := toFloat(args[1])
+	now := toInt64(args[2])
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	bucket, ok := b.buckets[key]
+	if !ok {
+		bucket = &memoryBucket{level: capacity, ts: now}
+		b.buckets[key] = bucket
+	}
+	elapsed := math.Max(0, float64(now-bucket.ts)) / 1000
+	tokens := math.Min(capacity, bucket.level+elapsed*rate)
+
+	var allowed, retryAfter int64
+	if tokens >= 1 {
+		allowed = 1
+		tokens--
+	} else {
+		retryAfter = int64(math.Ceil((1 - tokens) / rate * 1000))
+	}
+	bucket.level = tokens
+	bucket.ts = now
+	bucket.expiresAt = time.UnixMilli(now).Add(time.Duration(math.Ceil(capacity/rate*1000)+1000) * time.Millisecond)
+
+	return []interface{}{allowed, int64(tokens), retryAfter}, nil
+}
+
+func (b *InMemoryBackend) evalLeakyBucket(key string, args []interface{}) (interface{}, error) {
+	capacity := toFloat(args[0])
+	rate := toFloat(args[1])
+	now := toInt64(args[2])
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	bucket, ok := b.buckets[key]
+	if !ok {
+		bucket = &memoryBucket{level: 0, ts: now}
+		b.buckets[key] = bucket
+	}
+	elapsed := math.Max(0, float64(now-bucket.ts)) / 1000
+	level := math.Max(0, bucket.level-elapsed*rate)
+
+	var allowed, retryAfter int64
+	if level < capacity {
+		allowed = 1
+		level++
+	} else {
+		retryAfter = int64(math.Ceil((level - capacity + 1) / rate * 1000))
+	}
+	bucket.level = level
+	bucket.ts = now
+	bucket.expiresAt = time.UnixMilli(now).Add(time.Duration(math.Ceil(capacity/rate*1000)+1000) * time.Millisecond)
+
+	return []interface{}{allowed, int64(capacity - level), retryAfter}, nil
+}
+
+func (b *InMemoryBackend) evalSlidingWindowLog(key string, args []interface{}) (interface{}, error) {
+	limit := toInt64(args[0])
+	window := toInt64(args[1])
+	now := toInt64(args[2])
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	l, ok := b.logs[key]
+	if !ok {
+		l = &memoryLog{}
+		b.logs[key] = l
+	}
+	kept := l.entries[:0]
+	for _, ts := range l.entries {
+		if ts > now-window {
+			kept = append(kept, ts)
+		}
+	}
+	l.entries = kept
+
+	var allowed int64
+	count := int64(len(l.entries))
+	if count < limit {
+		allowed = 1
+		l.entries = append(l.entries, now)
+		count++
+	}
+	l.expiresAt = time.UnixMilli(now + window)
+
+	remaining := limit - count
+	if remaining < 0 {
+		remaining = 0
+	}
+
+	resetAt := now + window
+	if len(l.entries) > 0 {
+		resetAt = l.entries[0] + window
+	}
+
+	return []interface{}{allowed, remaining, resetAt}, nil
+}
+
+func toFloat(v interface{}) float64 {
+	switch n := v.(type) {
+	case int:
+		return float64(n)
+	case int64:
+		return float64(n)
+	case float64:
+		return n
+	default:
+		return 0
+	}
+}
+
+func toInt64(v interface{}) int64 {
+	switch n := v.(type) {
+	case int:
+		return int64(n)
+	case int64:
+		return n
+	case float64:
+		return int64(n)
+	default:
+		return 0
+	}
+}
+
+func (b *InMemoryBackend) ZAdd(ctx context.Context, key string, member string, score float64) (int64, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	z, ok := b.zsets[key]
+	if !ok {
+		z = &memoryZSet{scores: make(map[string]float64)}
+		b.zsets[key] = z
+	}
+	_, existed := z.scores[member]
+	z.scores[member] = score
+	if existed {
+		return 0, nil
+	}
+	return 1, nil
+}
+
+func (b *InMemoryBackend) ZScore(ctx context.Context, key string, member string) (float64, bool, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	z, ok := b.zsets[key]
+	if !ok {
+		return 0, false, nil
+	}
+	score, ok := z.scores[member]
+	return score, ok, nil
+}
+
+func (b *InMemoryBackend) ZMembers(ctx context.Context, key string) ([]ZMember, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	z, ok := b.zsets[key]
+	if !ok {
+		return nil, nil
+	}
+	members := make([]ZMember, 0, len(z.scores))
+	for member, score := range z.scores {
+		members = append(members, ZMember{Member: member, Score: score})
+	}
+	return members, nil
+}
+
+func (b *InMemoryBackend) ZRemRangeByScore(ctx context.Context, key string, min, max float64) (int64, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	z, ok := b.zsets[key]
+	if !ok {
+		return 0, nil
+	}
+	var removed int64
+	for member, score := range z.scores {
+		if score >= min && score <= max {
+			delete(z.scores, member)
+			removed++
+		}
+	}
+	return removed, nil
+}
@@ -0,0 +1,156 @@
+package rateLimiter
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestInMemoryBackendSet(t *testing.T) {
+	b := NewInMemoryBackend()
+	defer b.Close()
+	ctx := context.Background()
+
+	added, err := b.SAdd(ctx, "set", "a")
+	if err != nil || added != 1 {
+		t.Fatalf("SAdd() = %d, %v, want 1, nil", added, err)
+	}
+	if added, _ := b.SAdd(ctx, "set", "a"); added != 0 {
+		t.Fatalf("SAdd() of existing member = %d, want 0", added)
+	}
+
+	members, err := b.SMembers(ctx, "set")
+	if err != nil || len(members) != 1 || members[0] != "a" {
+		t.Fatalf("SMembers() = %v, %v, want [a], nil", members, err)
+	}
+
+	removed, err := b.SRem(ctx, "set", "a")
+	if err != nil || removed != 1 {
+		t.Fatalf("SRem() = %d, %v, want 1, nil", removed, err)
+	}
+	if members, _ := b.SMembers(ctx, "set"); len(members) != 0 {
+		t.Fatalf("SMembers() after SRem = %v, want empty", members)
+	}
+}
+
+func TestInMemoryBackendFrequencyLimit(t *testing.T) {
+	b := NewInMemoryBackend()
+	defer b.Close()
+	ctx := context.Background()
+
+	for i := 1; i <= 3; i++ {
+		times, err := b.FrequencyLimit(ctx, "key", 0, time.Minute)
+		if err != nil {
+			t.Fatalf("FrequencyLimit() error = %v", err)
+		}
+		if times != i {
+			t.Fatalf("FrequencyLimit() call %d = %d, want %d", i, times, i)
+		}
+	}
+}
+
+func TestInMemoryBackendFrequencyLimitResetsOnExpiry(t *testing.T) {
+	b := NewInMemoryBackend()
+	defer b.Close()
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		if _, err := b.FrequencyLimit(ctx, "key", 0, 10*time.Millisecond); err != nil {
+			t.Fatalf("FrequencyLimit() error = %v", err)
+		}
+	}
+
+	time.Sleep(30 * time.Millisecond)
+
+	times, err := b.FrequencyLimit(ctx, "key", 0, 10*time.Millisecond)
+	if err != nil {
+		t.Fatalf("FrequencyLimit() error = %v", err)
+	}
+	if times != 1 {
+		t.Fatalf("FrequencyLimit() after window elapsed = %d, want 1", times)
+	}
+}
+
+func TestInMemoryBackendIncrIgnoresExpiredCounter(t *testing.T) {
+	b := NewInMemoryBackend()
+	defer b.Close()
+	ctx := context.Background()
+
+	if _, err := b.Incr(ctx, "key"); err != nil {
+		t.Fatalf("Incr() error = %v", err)
+	}
+	if _, err := b.Expire(ctx, "key", 10*time.Millisecond); err != nil {
+		t.Fatalf("Expire() error = %v", err)
+	}
+
+	time.Sleep(30 * time.Millisecond)
+
+	count, err := b.Incr(ctx, "key")
+	if err != nil {
+		t.Fatalf("Incr() error = %v", err)
+	}
+	if count != 1 {
+		t.Fatalf("Incr() after expiry = %d, want 1", count)
+	}
+}
+
+func TestInMemoryBackendExpireAndDel(t *testing.T) {
+	b := NewInMemoryBackend()
+	defer b.Close()
+	ctx := context.Background()
+
+	if _, err := b.Incr(ctx, "counter"); err != nil {
+		t.Fatalf("Incr() error = %v", err)
+	}
+	if ok, err := b.Expire(ctx, "counter", time.Minute); err != nil || !ok {
+		t.Fatalf("Expire() = %v, %v, want true, nil", ok, err)
+	}
+	if ok, _ := b.Expire(ctx, "missing", time.Minute); ok {
+		t.Fatalf("Expire() of missing key = true, want false")
+	}
+
+	deleted, err := b.Del(ctx, "counter")
+	if err != nil || deleted != 1 {
+		t.Fatalf("Del() = %d, %v, want 1, nil", deleted, err)
+	}
+}
+
+func TestInMemoryBackendZSet(t *testing.T) {
+	b := NewInMemoryBackend()
+	defer b.Close()
+	ctx := context.Background()
+
+	if _, err := b.ZAdd(ctx, "z", "a", 100); err != nil {
+		t.Fatalf("ZAdd() error = %v", err)
+	}
+	if _, err := b.ZAdd(ctx, "z", "b", 200); err != nil {
+		t.Fatalf("ZAdd() error = %v", err)
+	}
+
+	score, ok, err := b.ZScore(ctx, "z", "a")
+	if err != nil || !ok || score != 100 {
+		t.Fatalf("ZScore() = %v, %v, %v, want 100, true, nil", score, ok, err)
+	}
+
+	removed, err := b.ZRemRangeByScore(ctx, "z", 0, 150)
+	if err != nil || removed != 1 {
+		t.Fatalf("ZRemRangeByScore() = %d, %v, want 1, nil", removed, err)
+	}
+	if _, ok, _ := b.ZScore(ctx, "z", "a"); ok {
+		t.Fatalf("ZScore() for removed member reports ok = true")
+	}
+
+	members, err := b.ZMembers(ctx, "z")
+	if err != nil || len(members) != 1 || members[0].Member != "b" {
+		t.Fatalf("ZMembers() = %v, %v, want [b]", members, err)
+	}
+}
+
+func TestInMemoryBackendEvalScriptUnsupported(t *testing.T) {
+	b := NewInMemoryBackend()
+	defer b.Close()
+
+	if _, err := b.EvalScript(context.Background(), "not-a-real-script", []string{"k"}); err != ErrScriptNotSupported {
+		t.Fatalf("EvalScript() error = %v, want ErrScriptNotSupported", err)
+	}
+}
@@ -0,0 +1,109 @@
+package rateLimiter
+
+import (
+	"context"
+	"time"
+)
+
+//MultiBackend reads from a local, in-process cache and writes through to a
+//remote backend (normally Redis), so hot-path reads like whitelist/blacklist
+//membership don't round-trip to Redis on every call. Writes always go to
+//Remote first since it is the source of truth; Local is then updated
+//best-effort to keep the cache warm.
+type MultiBackend struct {
+	Local  Backend
+	Remote Backend
+}
+
+func NewMultiBackend(local, remote Backend) *MultiBackend {
+	return &MultiBackend{Local: local, Remote: remote}
+}
+
+func (b *MultiBackend) Incr(ctx context.Context, key string) (int64, error) {
+	return b.Remote.Incr(ctx, key)
+}
+
+func (b *MultiBackend) Expire(ctx context.Context, key string, ttl time.Duration) (bool, error) {
+	return b.Remote.Expire(ctx, key, ttl)
+}
+
+func (b *MultiBackend) Del(ctx context.Context, keys ...string) (int64, error) {
+	n, err := b.Remote.Del(ctx, keys...)
+	if err != nil {
+		return n, err
+	}
+	b.Local.Del(ctx, keys...)
+	return n, nil
+}
+
+func (b *MultiBackend) SAdd(ctx context.Context, key string, member string) (int64, error) {
+	n, err := b.Remote.SAdd(ctx, key, member)
+	if err != nil {
+		return n, err
+	}
+	b.Local.SAdd(ctx, key, member)
+	return n, nil
+}
+
+func (b *MultiBackend) SRem(ctx context.Context, key string, member string) (int64, error) {
+	n, err := b.Remote.SRem(ctx, key, member)
+	if err != nil {
+		return n, err
+	}
+	b.Local.SRem(ctx, key, member)
+	return n, nil
+}
+
+//SMembers serves from Local when it has a cached copy, falling back to
+//Remote and repopulating Local on a miss.
+func (b *MultiBackend) SMembers(ctx context.Context, key string) ([]string, error) {
+	if members, err := b.Local.SMembers(ctx, key); err == nil && len(members) > 0 {
+		return members, nil
+	}
+	members, err := b.Remote.SMembers(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	for _, m := range members {
+		b.Local.SAdd(ctx, key, m)
+	}
+	return members, nil
+}
+
+func (b *MultiBackend) FrequencyLimit(ctx context.Context, key string, min int, duration time.Duration) (int, error) {
+	return b.Remote.FrequencyLimit(ctx, key, min, duration)
+}
+
+func (b *MultiBackend) TTL(ctx context.Context, key string) (time.Duration, error) {
+	return b.Remote.TTL(ctx, key)
+}
+
+func (b *MultiBackend) EvalScript(ctx context.Context, script string, keys []string, args ...interface{}) (interface{}, error) {
+	return b.Remote.EvalScript(ctx, script, keys, args...)
+}
+
+func (b *MultiBackend) ZAdd(ctx context.Context, key string, member string, score float64) (int64, error) {
+	n, err := b.Remote.ZAdd(ctx, key, member, score)
+	if err != nil {
+		return n, err
+	}
+	b.Local.ZAdd(ctx, key, member, score)
+	return n, nil
+}
+
+func (b *MultiBackend) ZScore(ctx context.Context, key string, member string) (float64, bool, error) {
+	return b.Remote.ZScore(ctx, key, member)
+}
+
+func (b *MultiBackend) ZRemRangeByScore(ctx context.Context, key string, min, max float64) (int64, error) {
+	n, err := b.Remote.ZRemRangeByScore(ctx, key, min, max)
+	if err != nil {
+		return n, err
+	}
+	b.Local.ZRemRangeByScore(ctx, key, min, max)
+	return n, nil
+}
+
+func (b *MultiBackend) ZMembers(ctx context.Context, key string) ([]ZMember, error) {
+	return b.Remote.ZMembers(ctx, key)
+}
@@ -0,0 +1,97 @@
+package rateLimiter
+
+import (
+	"context"
+	"github.com/go-tron/redis"
+	goredis "github.com/redis/go-redis/v9"
+	"strconv"
+	"time"
+)
+
+func formatScore(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
+
+//RedisBackend adapts *redis.Redis to Backend. This is the default backend
+//and preserves the behavior RateLimiter had before Backend existed.
+type RedisBackend struct {
+	Store *redis.Redis
+}
+
+func NewRedisBackend(store *redis.Redis) *RedisBackend {
+	return &RedisBackend{Store: store}
+}
+
+func (b *RedisBackend) Incr(ctx context.Context, key string) (int64, error) {
+	return b.Store.Incr(ctx, key).Result()
+}
+
+func (b *RedisBackend) Expire(ctx context.Context, key string, ttl time.Duration) (bool, error) {
+	return b.Store.Expire(ctx, key, ttl).Result()
+}
+
+func (b *RedisBackend) Del(ctx context.Context, keys ...string) (int64, error) {
+	return b.Store.Del(ctx, keys...).Result()
+}
+
+func (b *RedisBackend) SAdd(ctx context.Context, key string, member string) (int64, error) {
+	return b.Store.SAdd(ctx, key, member).Result()
+}
+
+func (b *RedisBackend) SRem(ctx context.Context, key string, member string) (int64, error) {
+	return b.Store.SRem(ctx, key, member).Result()
+}
+
+func (b *RedisBackend) SMembers(ctx context.Context, key string) ([]string, error) {
+	return b.Store.SMembers(ctx, key).Result()
+}
+
+func (b *RedisBackend) FrequencyLimit(ctx context.Context, key string, min int, duration time.Duration) (int, error) {
+	return b.Store.FrequencyLimit(ctx, key, min, duration)
+}
+
+func (b *RedisBackend) TTL(ctx context.Context, key string) (time.Duration, error) {
+	ttl, err := b.Store.PTTL(ctx, key).Result()
+	if err != nil {
+		return 0, err
+	}
+	if ttl < 0 {
+		return 0, nil
+	}
+	return ttl, nil
+}
+
+func (b *RedisBackend) EvalScript(ctx context.Context, script string, keys []string, args ...interface{}) (interface{}, error) {
+	return b.Store.Eval(ctx, script, keys, args...).Result()
+}
+
+func (b *RedisBackend) ZAdd(ctx context.Context, key string, member string, score float64) (int64, error) {
+	return b.Store.ZAdd(ctx, key, goredis.Z{Score: score, Member: member}).Result()
+}
+
+func (b *RedisBackend) ZScore(ctx context.Context, key string, member string) (float64, bool, error) {
+	score, err := b.Store.ZScore(ctx, key, member).Result()
+	if err != nil {
+		if err == redis.Nil {
+			return 0, false, nil
+		}
+		return 0, false, err
+	}
+	return score, true, nil
+}
+
+func (b *RedisBackend) ZRemRangeByScore(ctx context.Context, key string, min, max float64) (int64, error) {
+	return b.Store.ZRemRangeByScore(ctx, key, formatScore(min), formatScore(max)).Result()
+}
+
+func (b *RedisBackend) ZMembers(ctx context.Context, key string) ([]ZMember, error) {
+	result, err := b.Store.ZRangeWithScores(ctx, key, 0, -1).Result()
+	if err != nil {
+		return nil, err
+	}
+	members := make([]ZMember, 0, len(result))
+	for _, z := range result {
+		members = append(members, ZMember{Member: z.Member.(string), Score: z.Score})
+	}
+	return members, nil
+}
@@ -0,0 +1,152 @@
+package rateLimiter
+
+import (
+	"context"
+	"time"
+)
+
+//defaultTTLSweepInterval is used when Config.TTLSweepInterval is unset.
+const defaultTTLSweepInterval = time.Minute
+
+//ttlEntry is a blacklist entry that self-expires at expiresAt, backed by a
+//Redis sorted set (score = expiry unix ms) rather than the plain set used
+//by AddBlackList.
+type ttlEntry struct {
+	raw       string
+	expiresAt time.Time
+}
+
+//AddBlackListWithTTL blocks id until ttl elapses, after which it is reaped
+//automatically with no need to call RemoveBlackList. Entries are stored in
+//a Redis sorted set keyed by rl.blackListTTLKey so any node can rehydrate
+//them, and a local copy is kept for fast, allocation-free lookups in Check.
+func (rl *RateLimiter) AddBlackListWithTTL(id string, ttl time.Duration, pub bool) error {
+	expiresAt := time.Now().Add(ttl)
+	_, err := rl.Store.ZAdd(context.Background(), rl.blackListTTLKey, id, float64(expiresAt.UnixMilli()))
+	if err != nil {
+		return err
+	}
+
+	rl.ttlMu.Lock()
+	if idx := indexOfTTL(rl.blackListTTL, id); idx != -1 {
+		rl.blackListTTL[idx].expiresAt = expiresAt
+	} else {
+		rl.blackListTTL = append(rl.blackListTTL, ttlEntry{raw: id, expiresAt: expiresAt})
+	}
+	rl.ttlMu.Unlock()
+
+	rl.updateListSizes()
+	rl.fireEvent(EventListMutation, id)
+
+	if pub {
+		rl.publish(OpAddBlackListTTL, id, ttl)
+	}
+	return nil
+}
+
+func indexOfTTL(list []ttlEntry, raw string) int {
+	for i, e := range list {
+		if e.raw == raw {
+			return i
+		}
+	}
+	return -1
+}
+
+//isBlacklistedTTL reports whether id is blocked by a TTL entry. It reaps
+//already-expired entries from Redis via ZREMRANGEBYSCORE on every call, and
+//purges the same entries from the local copy, so the slice this checks
+//against never grows unbounded even without the background sweeper.
+func (rl *RateLimiter) isBlacklistedTTL(id string) bool {
+	now := time.Now()
+	rl.Store.ZRemRangeByScore(context.Background(), rl.blackListTTLKey, 0, float64(now.UnixMilli()))
+	rl.purgeBlackListTTL(now)
+
+	rl.ttlMu.Lock()
+	defer rl.ttlMu.Unlock()
+	for _, e := range rl.blackListTTL {
+		if e.raw == id {
+			return true
+		}
+	}
+	return false
+}
+
+func (rl *RateLimiter) purgeBlackListTTL(now time.Time) {
+	rl.ttlMu.Lock()
+	defer rl.ttlMu.Unlock()
+	kept := rl.blackListTTL[:0]
+	for _, e := range rl.blackListTTL {
+		if e.expiresAt.After(now) {
+			kept = append(kept, e)
+		}
+	}
+	rl.blackListTTL = kept
+}
+
+//hydrateBlackListTTL loads existing TTL entries from Redis at startup, the
+//same way New already hydrates the plain whitelist/blacklist from SMembers.
+func (rl *RateLimiter) hydrateBlackListTTL() {
+	members, err := rl.Store.ZMembers(context.Background(), rl.blackListTTLKey)
+	if err != nil {
+		return
+	}
+	now := time.Now()
+	rl.ttlMu.Lock()
+	defer rl.ttlMu.Unlock()
+	for _, m := range members {
+		expiresAt := time.UnixMilli(int64(m.Score))
+		if expiresAt.After(now) {
+			rl.blackListTTL = append(rl.blackListTTL, ttlEntry{raw: m.Member, expiresAt: expiresAt})
+		}
+	}
+}
+
+//syncBlackListTTL is called when this node receives an addBlackListTTL
+//pub/sub message from a peer: it looks the id's expiry up in Redis; the
+//message's TTL field is not used.
+func (rl *RateLimiter) syncBlackListTTL(id string) error {
+	score, ok, err := rl.Store.ZScore(context.Background(), rl.blackListTTLKey, id)
+	if err != nil || !ok {
+		return err
+	}
+	expiresAt := time.UnixMilli(int64(score))
+	rl.ttlMu.Lock()
+	if idx := indexOfTTL(rl.blackListTTL, id); idx != -1 {
+		rl.blackListTTL[idx].expiresAt = expiresAt
+	} else {
+		rl.blackListTTL = append(rl.blackListTTL, ttlEntry{raw: id, expiresAt: expiresAt})
+	}
+	rl.ttlMu.Unlock()
+	return nil
+}
+
+//startTTLSweeper runs until Close stops it, purging expired TTL blacklist
+//entries from the local copy on a configurable interval so long-idle
+//RateLimiters (no Check calls to trigger the lazy reap) don't leak memory.
+func (rl *RateLimiter) startTTLSweeper() {
+	interval := rl.TTLSweepInterval
+	if interval <= 0 {
+		interval = defaultTTLSweepInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-rl.closeSweep:
+			return
+		case now := <-ticker.C:
+			rl.purgeBlackListTTL(now)
+		}
+	}
+}
+
+//Close stops the background TTL sweeper goroutine started by New, and
+//cancels the Subscriber.Subscribe goroutine started by subscribe, if any.
+//It is safe to call more than once.
+func (rl *RateLimiter) Close() {
+	rl.closeOnce.Do(func() {
+		close(rl.closeSweep)
+		rl.subscribeCancel()
+	})
+}
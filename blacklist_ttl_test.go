@@ -0,0 +1,114 @@
+package rateLimiter
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func newTestRateLimiter(t *testing.T) *RateLimiter {
+	t.Helper()
+	store := NewInMemoryBackend()
+	rl := New(&Config{
+		Name:     "ttl-test",
+		Duration: time.Minute,
+		Store:    store,
+	})
+	t.Cleanup(rl.Close)
+	t.Cleanup(store.Close)
+	return rl
+}
+
+func TestAddBlackListWithTTLBlocksUntilExpiry(t *testing.T) {
+	rl := newTestRateLimiter(t)
+
+	if err := rl.AddBlackListWithTTL("1.2.3.4", time.Hour, false); err != nil {
+		t.Fatalf("AddBlackListWithTTL() error = %v", err)
+	}
+	if !rl.isBlacklistedTTL("1.2.3.4") {
+		t.Fatalf("isBlacklistedTTL() = false, want true right after AddBlackListWithTTL")
+	}
+
+	if err := rl.AddBlackListWithTTL("5.6.7.8", -time.Hour, false); err != nil {
+		t.Fatalf("AddBlackListWithTTL() error = %v", err)
+	}
+	if rl.isBlacklistedTTL("5.6.7.8") {
+		t.Fatalf("isBlacklistedTTL() = true, want false once expiresAt is in the past")
+	}
+}
+
+func TestAddBlackListWithTTLRenewalDedupes(t *testing.T) {
+	rl := newTestRateLimiter(t)
+
+	if err := rl.AddBlackListWithTTL("1.2.3.4", time.Hour, false); err != nil {
+		t.Fatalf("AddBlackListWithTTL() error = %v", err)
+	}
+	if err := rl.AddBlackListWithTTL("1.2.3.4", 2*time.Hour, false); err != nil {
+		t.Fatalf("AddBlackListWithTTL() error = %v", err)
+	}
+
+	rl.ttlMu.Lock()
+	count := 0
+	for _, e := range rl.blackListTTL {
+		if e.raw == "1.2.3.4" {
+			count++
+		}
+	}
+	entries := len(rl.blackListTTL)
+	rl.ttlMu.Unlock()
+
+	if count != 1 {
+		t.Fatalf("renewing the same id produced %d entries, want 1", count)
+	}
+	if entries != 1 {
+		t.Fatalf("blackListTTL has %d entries, want 1", entries)
+	}
+}
+
+func TestSyncBlackListTTLRenewalDedupes(t *testing.T) {
+	rl := newTestRateLimiter(t)
+
+	if _, err := rl.Store.ZAdd(context.Background(), rl.blackListTTLKey, "1.2.3.4", float64(time.Now().Add(time.Hour).UnixMilli())); err != nil {
+		t.Fatalf("ZAdd() error = %v", err)
+	}
+	if err := rl.syncBlackListTTL("1.2.3.4"); err != nil {
+		t.Fatalf("syncBlackListTTL() error = %v", err)
+	}
+	if _, err := rl.Store.ZAdd(context.Background(), rl.blackListTTLKey, "1.2.3.4", float64(time.Now().Add(2*time.Hour).UnixMilli())); err != nil {
+		t.Fatalf("ZAdd() error = %v", err)
+	}
+	if err := rl.syncBlackListTTL("1.2.3.4"); err != nil {
+		t.Fatalf("syncBlackListTTL() error = %v", err)
+	}
+
+	rl.ttlMu.Lock()
+	defer rl.ttlMu.Unlock()
+	count := 0
+	for _, e := range rl.blackListTTL {
+		if e.raw == "1.2.3.4" {
+			count++
+		}
+	}
+	if count != 1 {
+		t.Fatalf("syncing the same id twice produced %d entries, want 1", count)
+	}
+}
+
+func TestPurgeBlackListTTLRemovesExpiredOnly(t *testing.T) {
+	rl := newTestRateLimiter(t)
+
+	rl.ttlMu.Lock()
+	rl.blackListTTL = []ttlEntry{
+		{raw: "expired", expiresAt: time.Now().Add(-time.Minute)},
+		{raw: "live", expiresAt: time.Now().Add(time.Hour)},
+	}
+	rl.ttlMu.Unlock()
+
+	rl.purgeBlackListTTL(time.Now())
+
+	rl.ttlMu.Lock()
+	defer rl.ttlMu.Unlock()
+	if len(rl.blackListTTL) != 1 || rl.blackListTTL[0].raw != "live" {
+		t.Fatalf("blackListTTL after purge = %v, want only \"live\"", rl.blackListTTL)
+	}
+}
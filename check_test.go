@@ -0,0 +1,238 @@
+package rateLimiter
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func newCheckTestRateLimiter(t *testing.T, c *Config) *RateLimiter {
+	t.Helper()
+	store := NewInMemoryBackend()
+	c.Store = store
+	if c.Name == "" {
+		c.Name = "check-test"
+	}
+	if c.Duration == 0 {
+		c.Duration = time.Minute
+	}
+	rl := New(c)
+	t.Cleanup(rl.Close)
+	t.Cleanup(store.Close)
+	return rl
+}
+
+func TestCheckAllowed(t *testing.T) {
+	rl := newCheckTestRateLimiter(t, &Config{WarningTimes: 5, BlockTimes: 10})
+
+	times, err := rl.Check("1.1.1.1")
+	if err != nil {
+		t.Fatalf("Check() error = %v, want nil", err)
+	}
+	if times != 1 {
+		t.Fatalf("Check() times = %d, want 1", times)
+	}
+}
+
+func TestCheckWarning(t *testing.T) {
+	var events []Event
+	rl := newCheckTestRateLimiter(t, &Config{
+		WarningTimes: 2,
+		BlockTimes:   5,
+		EventHook:    func(e Event) { events = append(events, e) },
+	})
+
+	if _, err := rl.Check("1.1.1.1"); err != nil {
+		t.Fatalf("Check() call 1 error = %v, want nil", err)
+	}
+	_, err := rl.Check("1.1.1.1")
+	if err != rl.WarningError {
+		t.Fatalf("Check() call 2 error = %v, want WarningError", err)
+	}
+	// A later call past WarningTimes repeats the warning but must not refire
+	// EventFirstWarning.
+	if _, err := rl.Check("1.1.1.1"); err != rl.WarningError {
+		t.Fatalf("Check() call 3 error = %v, want WarningError", err)
+	}
+
+	var warnings int
+	for _, e := range events {
+		if e.Type == EventFirstWarning {
+			warnings++
+		}
+	}
+	if warnings != 1 {
+		t.Fatalf("EventFirstWarning fired %d times, want exactly 1", warnings)
+	}
+}
+
+func TestCheckBlockedFixedWindowWithCooldown(t *testing.T) {
+	var events []Event
+	rl := newCheckTestRateLimiter(t, &Config{
+		BlockTimes:    1,
+		BlockDuration: time.Hour,
+		EventHook:     func(e Event) { events = append(events, e) },
+	})
+
+	result, _, err := rl.check("1.1.1.1")
+	if err != rl.BlockError {
+		t.Fatalf("check() error = %v, want BlockError", err)
+	}
+	if result.Allowed {
+		t.Fatalf("check() Allowed = true, want false")
+	}
+	if result.RetryAfter != time.Hour {
+		t.Fatalf("RetryAfter = %v, want %v", result.RetryAfter, time.Hour)
+	}
+	if ok, _ := rl.IsBlacklisted("1.1.1.1"); ok {
+		t.Fatalf("IsBlacklisted() = true, want false: BlockDuration>0 cools down rather than permanently blacklisting")
+	}
+
+	var blocked int
+	for _, e := range events {
+		if e.Type == EventBlocked {
+			blocked++
+		}
+	}
+	if blocked != 1 {
+		t.Fatalf("EventBlocked fired %d times, want 1", blocked)
+	}
+}
+
+func TestCheckBlockedFixedWindowPermanent(t *testing.T) {
+	rl := newCheckTestRateLimiter(t, &Config{BlockTimes: 1, BlockDuration: 0})
+
+	if _, _, err := rl.check("1.1.1.1"); err != rl.BlockError {
+		t.Fatalf("check() error = %v, want BlockError", err)
+	}
+	if ok, reason := rl.IsBlacklisted("1.1.1.1"); !ok || reason != MatchExact {
+		t.Fatalf("IsBlacklisted() = %v, %q, want true, exact: BlockDuration=0 blacklists permanently", ok, reason)
+	}
+}
+
+func TestCheckWhitelistedShortCircuitsBeforeStore(t *testing.T) {
+	rl := newCheckTestRateLimiter(t, &Config{BlockTimes: 1})
+	if err := rl.AddWhiteList("1.1.1.1", false); err != nil {
+		t.Fatalf("AddWhiteList() error = %v", err)
+	}
+
+	for i := 0; i < 3; i++ {
+		times, err := rl.Check("1.1.1.1")
+		if err != nil || times != 0 {
+			t.Fatalf("Check() call %d = %d, %v, want 0, nil", i, times, err)
+		}
+	}
+}
+
+func TestCheckBlacklistedFiresBlockedEvent(t *testing.T) {
+	var events []Event
+	rl := newCheckTestRateLimiter(t, &Config{EventHook: func(e Event) { events = append(events, e) }})
+	if err := rl.AddBlackList("1.1.1.1", false); err != nil {
+		t.Fatalf("AddBlackList() error = %v", err)
+	}
+	events = nil // AddBlackList itself fires EventListMutation; only count Check's event
+
+	if _, err := rl.Check("1.1.1.1"); err != rl.BlockError {
+		t.Fatalf("Check() error = %v, want BlockError", err)
+	}
+	if len(events) != 1 || events[0].Type != EventBlocked {
+		t.Fatalf("events = %v, want exactly one EventBlocked", events)
+	}
+}
+
+func TestCheckBlacklistedTTLFiresBlockedEvent(t *testing.T) {
+	var events []Event
+	rl := newCheckTestRateLimiter(t, &Config{EventHook: func(e Event) { events = append(events, e) }})
+	if err := rl.AddBlackListWithTTL("1.1.1.1", time.Hour, false); err != nil {
+		t.Fatalf("AddBlackListWithTTL() error = %v", err)
+	}
+	events = nil // AddBlackListWithTTL itself fires EventListMutation
+
+	if _, err := rl.Check("1.1.1.1"); err != rl.BlockError {
+		t.Fatalf("Check() error = %v, want BlockError", err)
+	}
+	if len(events) != 1 || events[0].Type != EventBlocked {
+		t.Fatalf("events = %v, want exactly one EventBlocked", events)
+	}
+}
+
+func TestCheckWithStrategyDelegates(t *testing.T) {
+	var events []Event
+	rl := newCheckTestRateLimiter(t, &Config{
+		Strategy:  NewTokenBucket(1, 1),
+		EventHook: func(e Event) { events = append(events, e) },
+	})
+
+	result, err := rl.CheckResult("1.1.1.1")
+	if err != nil || !result.Allowed {
+		t.Fatalf("CheckResult() call 1 = %+v, %v, want allowed, nil", result, err)
+	}
+
+	result, err = rl.CheckResult("1.1.1.1")
+	if err != rl.BlockError {
+		t.Fatalf("CheckResult() call 2 error = %v, want BlockError", err)
+	}
+	if result.Allowed {
+		t.Fatalf("CheckResult() call 2 Allowed = true, want false")
+	}
+
+	var blocked int
+	for _, e := range events {
+		if e.Type == EventBlocked {
+			blocked++
+		}
+	}
+	if blocked != 1 {
+		t.Fatalf("EventBlocked fired %d times, want 1", blocked)
+	}
+}
+
+func TestCheckResetAtDoesNotDriftWithinAWindow(t *testing.T) {
+	rl := newCheckTestRateLimiter(t, &Config{Duration: 2 * time.Second})
+
+	first, err := rl.CheckResult("1.1.1.1")
+	if err != nil {
+		t.Fatalf("CheckResult() call 1 error = %v", err)
+	}
+
+	time.Sleep(300 * time.Millisecond)
+
+	second, err := rl.CheckResult("1.1.1.1")
+	if err != nil {
+		t.Fatalf("CheckResult() call 2 error = %v", err)
+	}
+	if diff := second.ResetAt.Sub(first.ResetAt); diff < -50*time.Millisecond || diff > 50*time.Millisecond {
+		t.Fatalf("ResetAt moved by %v between calls in the same window, want it pinned to the window's real expiry", diff)
+	}
+}
+
+func TestCheckRecordsMetrics(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	rl := newCheckTestRateLimiter(t, &Config{BlockTimes: 2, MetricsRegistry: registry})
+
+	if _, err := rl.Check("1.1.1.1"); err != nil {
+		t.Fatalf("Check() call 1 error = %v", err)
+	}
+	if allowed := testutil.ToFloat64(rl.metrics.checksTotal.WithLabelValues("allowed")); allowed != 1 {
+		t.Fatalf("checks_total{result=allowed} = %v, want 1", allowed)
+	}
+
+	if _, err := rl.Check("1.1.1.1"); err != rl.BlockError {
+		t.Fatalf("Check() call 2 error = %v, want BlockError", err)
+	}
+	if blocked := testutil.ToFloat64(rl.metrics.checksTotal.WithLabelValues("blocked")); blocked != 1 {
+		t.Fatalf("checks_total{result=blocked} = %v, want 1", blocked)
+	}
+
+	if err := rl.AddWhiteList("2.2.2.2", false); err != nil {
+		t.Fatalf("AddWhiteList() error = %v", err)
+	}
+	if _, err := rl.Check("2.2.2.2"); err != nil {
+		t.Fatalf("Check() whitelisted error = %v", err)
+	}
+	if whitelisted := testutil.ToFloat64(rl.metrics.checksTotal.WithLabelValues("whitelisted")); whitelisted != 1 {
+		t.Fatalf("checks_total{result=whitelisted} = %v, want 1", whitelisted)
+	}
+}
@@ -0,0 +1,66 @@
+package rateLimiter
+
+import "net"
+
+//MatchReason reports how an id matched an entry in the whitelist/blacklist,
+//so callers can tell a literal match from a CIDR range match.
+type MatchReason string
+
+const (
+	MatchNone  MatchReason = ""
+	MatchExact MatchReason = "exact"
+	MatchCIDR  MatchReason = "cidr"
+)
+
+//listEntry is either a literal id or, when it parses as CIDR notation
+//(e.g. "10.0.0.0/8", "2001:db8::/32"), a compiled IP range. raw is always
+//kept so the entry round-trips to Redis exactly as it was added.
+type listEntry struct {
+	raw string
+	net *net.IPNet
+}
+
+func newListEntry(id string) listEntry {
+	if _, ipNet, err := net.ParseCIDR(id); err == nil {
+		return listEntry{raw: id, net: ipNet}
+	}
+	return listEntry{raw: id}
+}
+
+func (e listEntry) matches(id string) (bool, MatchReason) {
+	if e.raw == id {
+		return true, MatchExact
+	}
+	if e.net != nil {
+		if ip := net.ParseIP(id); ip != nil && e.net.Contains(ip) {
+			return true, MatchCIDR
+		}
+	}
+	return false, MatchNone
+}
+
+func matchList(entries []listEntry, id string) (bool, MatchReason) {
+	for _, e := range entries {
+		if ok, reason := e.matches(id); ok {
+			return true, reason
+		}
+	}
+	return false, MatchNone
+}
+
+func rawStrings(entries []listEntry) []string {
+	out := make([]string, 0, len(entries))
+	for _, e := range entries {
+		out = append(out, e.raw)
+	}
+	return out
+}
+
+func indexOfRaw(entries []listEntry, id string) int {
+	for i, e := range entries {
+		if e.raw == id {
+			return i
+		}
+	}
+	return -1
+}
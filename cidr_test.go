@@ -0,0 +1,69 @@
+package rateLimiter
+
+import "testing"
+
+func TestNewListEntryMatches(t *testing.T) {
+	cases := []struct {
+		name       string
+		entry      string
+		id         string
+		wantMatch  bool
+		wantReason MatchReason
+	}{
+		{"exact literal", "10.0.0.1", "10.0.0.1", true, MatchExact},
+		{"literal mismatch", "10.0.0.1", "10.0.0.2", false, MatchNone},
+		{"ipv4 cidr hit", "10.0.0.0/8", "10.1.2.3", true, MatchCIDR},
+		{"ipv4 cidr miss", "10.0.0.0/8", "11.0.0.1", false, MatchNone},
+		{"ipv6 cidr hit", "2001:db8::/32", "2001:db8::1", true, MatchCIDR},
+		{"non-ip id against cidr", "10.0.0.0/8", "not-an-ip", false, MatchNone},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := newListEntry(tc.entry)
+			match, reason := e.matches(tc.id)
+			if match != tc.wantMatch || reason != tc.wantReason {
+				t.Fatalf("matches(%q) = %v, %q, want %v, %q", tc.id, match, reason, tc.wantMatch, tc.wantReason)
+			}
+			if e.raw != tc.entry {
+				t.Fatalf("raw = %q, want %q", e.raw, tc.entry)
+			}
+		})
+	}
+}
+
+func TestMatchList(t *testing.T) {
+	entries := []listEntry{newListEntry("192.168.1.1"), newListEntry("10.0.0.0/8")}
+
+	if ok, reason := matchList(entries, "192.168.1.1"); !ok || reason != MatchExact {
+		t.Fatalf("matchList() literal = %v, %q, want true, exact", ok, reason)
+	}
+	if ok, reason := matchList(entries, "10.2.3.4"); !ok || reason != MatchCIDR {
+		t.Fatalf("matchList() cidr = %v, %q, want true, cidr", ok, reason)
+	}
+	if ok, reason := matchList(entries, "8.8.8.8"); ok || reason != MatchNone {
+		t.Fatalf("matchList() unmatched = %v, %q, want false, none", ok, reason)
+	}
+}
+
+func TestIndexOfRawAndRawStrings(t *testing.T) {
+	entries := []listEntry{newListEntry("a"), newListEntry("10.0.0.0/8")}
+
+	if idx := indexOfRaw(entries, "10.0.0.0/8"); idx != 1 {
+		t.Fatalf("indexOfRaw() = %d, want 1", idx)
+	}
+	if idx := indexOfRaw(entries, "missing"); idx != -1 {
+		t.Fatalf("indexOfRaw() = %d, want -1", idx)
+	}
+
+	raw := rawStrings(entries)
+	want := []string{"a", "10.0.0.0/8"}
+	if len(raw) != len(want) {
+		t.Fatalf("rawStrings() = %v, want %v", raw, want)
+	}
+	for i := range want {
+		if raw[i] != want[i] {
+			t.Fatalf("rawStrings()[%d] = %q, want %q", i, raw[i], want[i])
+		}
+	}
+}
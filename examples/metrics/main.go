@@ -0,0 +1,34 @@
+//Command metrics demonstrates wiring RateLimiter's Prometheus collectors and
+//EventHook into a standard /metrics endpoint. Run it and scrape
+//http://localhost:2112/metrics.
+package main
+
+import (
+	"log"
+	"net/http"
+	"time"
+
+	rateLimiter "github.com/go-tron/rate-limiter"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+func main() {
+	registry := prometheus.NewRegistry()
+
+	rl := rateLimiter.New(&rateLimiter.Config{
+		Name:            "example",
+		Duration:        time.Minute,
+		WarningTimes:    10,
+		BlockTimes:      20,
+		Store:           rateLimiter.NewInMemoryBackend(),
+		MetricsRegistry: registry,
+		EventHook: func(e rateLimiter.Event) {
+			log.Printf("rate limiter event: %+v", e)
+		},
+	})
+	defer rl.Close()
+
+	http.Handle("/metrics", promhttp.HandlerFor(registry, promhttp.HandlerOpts{}))
+	log.Fatal(http.ListenAndServe(":2112", nil))
+}
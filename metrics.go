@@ -0,0 +1,104 @@
+package rateLimiter
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+//EventType identifies the kind of transition an EventHook is notified about.
+type EventType string
+
+const (
+	EventFirstWarning EventType = "first_warning"
+	EventBlocked      EventType = "blocked"
+	EventListMutation EventType = "list_mutation"
+)
+
+//EventHook is called on a warning/block transition or a whitelist/blacklist
+//mutation. Implementations should return quickly; Check calls it inline.
+type EventHook func(Event)
+
+//Event is passed to Config.EventHook on a warning/block transition or a
+//whitelist/blacklist mutation, so callers can wire it into logging/alerting
+//without polling GetWhiteList/GetBlackList.
+type Event struct {
+	Type EventType
+	Name string
+	ID   string
+	Time time.Time
+}
+
+//metrics holds the Prometheus collectors registered against
+//Config.MetricsRegistry. It is nil on a RateLimiter built without one, and
+//every call site guards on that before touching it.
+type metrics struct {
+	checksTotal   *prometheus.CounterVec
+	blacklistSize prometheus.Gauge
+	whitelistSize prometheus.Gauge
+	checkDuration prometheus.Histogram
+}
+
+func newMetrics(registry *prometheus.Registry, name string) *metrics {
+	constLabels := prometheus.Labels{"name": name}
+	m := &metrics{
+		checksTotal: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name:        "rate_limiter_checks_total",
+			Help:        "Total number of RateLimiter.Check calls by outcome.",
+			ConstLabels: constLabels,
+		}, []string{"result"}),
+		blacklistSize: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name:        "rate_limiter_blacklist_size",
+			Help:        "Current number of blacklist entries, including CIDR ranges and TTL entries.",
+			ConstLabels: constLabels,
+		}),
+		whitelistSize: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name:        "rate_limiter_whitelist_size",
+			Help:        "Current number of whitelist entries, including CIDR ranges.",
+			ConstLabels: constLabels,
+		}),
+		checkDuration: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Name:        "rate_limiter_store_check_duration_seconds",
+			Help:        "Latency of the Store.FrequencyLimit/Strategy.Allow call made by Check.",
+			ConstLabels: constLabels,
+			Buckets:     prometheus.DefBuckets,
+		}),
+	}
+	registry.MustRegister(m.checksTotal, m.blacklistSize, m.whitelistSize, m.checkDuration)
+	return m
+}
+
+func (rl *RateLimiter) recordCheck(result string, duration time.Duration) {
+	if rl.metrics == nil {
+		return
+	}
+	rl.metrics.checksTotal.WithLabelValues(result).Inc()
+	//A zero duration marks a check that made no Store/Strategy call (a
+	//whitelist/blacklist hit), so there is no latency to observe.
+	if duration > 0 {
+		rl.metrics.checkDuration.Observe(duration.Seconds())
+	}
+}
+
+func (rl *RateLimiter) updateListSizes() {
+	if rl.metrics == nil {
+		return
+	}
+	rl.listMu.RLock()
+	whitelistSize := len(rl.whiteList)
+	blackListLen := len(rl.blackList)
+	rl.listMu.RUnlock()
+	rl.metrics.whitelistSize.Set(float64(whitelistSize))
+
+	rl.ttlMu.Lock()
+	blacklistSize := blackListLen + len(rl.blackListTTL)
+	rl.ttlMu.Unlock()
+	rl.metrics.blacklistSize.Set(float64(blacklistSize))
+}
+
+func (rl *RateLimiter) fireEvent(eventType EventType, id string) {
+	if rl.EventHook == nil {
+		return
+	}
+	rl.EventHook(Event{Type: eventType, Name: rl.Name, ID: id, Time: time.Now()})
+}
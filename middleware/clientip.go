@@ -0,0 +1,54 @@
+package middleware
+
+import (
+	"net"
+	"net/http"
+	"strings"
+)
+
+//ClientIP resolves the real client address behind a load balancer. It trusts
+//X-Forwarded-For/X-Real-IP only when the immediate peer (r.RemoteAddr) is
+//within trustedProxies; otherwise it falls back to r.RemoteAddr so a client
+//can't spoof its own IP by setting those headers directly.
+func ClientIP(r *http.Request, trustedProxies []*net.IPNet) string {
+	remoteIP := hostOf(r.RemoteAddr)
+
+	if !isTrusted(remoteIP, trustedProxies) {
+		return remoteIP
+	}
+
+	if fwd := r.Header.Get("X-Forwarded-For"); fwd != "" {
+		parts := strings.Split(fwd, ",")
+		return strings.TrimSpace(parts[0])
+	}
+
+	if real := r.Header.Get("X-Real-IP"); real != "" {
+		return real
+	}
+
+	return remoteIP
+}
+
+func hostOf(remoteAddr string) string {
+	host, _, err := net.SplitHostPort(remoteAddr)
+	if err != nil {
+		return remoteAddr
+	}
+	return host
+}
+
+func isTrusted(ip string, trustedProxies []*net.IPNet) bool {
+	if len(trustedProxies) == 0 {
+		return false
+	}
+	parsed := net.ParseIP(ip)
+	if parsed == nil {
+		return false
+	}
+	for _, cidr := range trustedProxies {
+		if cidr.Contains(parsed) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,79 @@
+package middleware
+
+import (
+	"net"
+	"net/http"
+	"testing"
+)
+
+func trustedProxyCIDR(t *testing.T, cidr string) []*net.IPNet {
+	t.Helper()
+	_, ipNet, err := net.ParseCIDR(cidr)
+	if err != nil {
+		t.Fatalf("ParseCIDR(%q) error = %v", cidr, err)
+	}
+	return []*net.IPNet{ipNet}
+}
+
+func TestClientIPIgnoresHeadersWithNoTrustedProxies(t *testing.T) {
+	r := &http.Request{RemoteAddr: "203.0.113.5:1234", Header: http.Header{}}
+	r.Header.Set("X-Forwarded-For", "198.51.100.7")
+
+	if ip := ClientIP(r, nil); ip != "203.0.113.5" {
+		t.Fatalf("ClientIP() = %q, want RemoteAddr since no proxy is trusted", ip)
+	}
+}
+
+func TestClientIPIgnoresHeadersFromUntrustedPeer(t *testing.T) {
+	r := &http.Request{RemoteAddr: "203.0.113.5:1234", Header: http.Header{}}
+	r.Header.Set("X-Forwarded-For", "198.51.100.7")
+
+	proxies := trustedProxyCIDR(t, "10.0.0.0/8")
+	if ip := ClientIP(r, proxies); ip != "203.0.113.5" {
+		t.Fatalf("ClientIP() = %q, want RemoteAddr: peer is not within trustedProxies", ip)
+	}
+}
+
+func TestClientIPTrustsForwardedForFromTrustedPeer(t *testing.T) {
+	r := &http.Request{RemoteAddr: "10.0.0.1:1234", Header: http.Header{}}
+	r.Header.Set("X-Forwarded-For", "198.51.100.7, 10.0.0.1")
+
+	proxies := trustedProxyCIDR(t, "10.0.0.0/8")
+	if ip := ClientIP(r, proxies); ip != "198.51.100.7" {
+		t.Fatalf("ClientIP() = %q, want leftmost X-Forwarded-For entry", ip)
+	}
+}
+
+func TestClientIPFallsBackToRealIPFromTrustedPeer(t *testing.T) {
+	r := &http.Request{RemoteAddr: "10.0.0.1:1234", Header: http.Header{}}
+	r.Header.Set("X-Real-IP", "198.51.100.7")
+
+	proxies := trustedProxyCIDR(t, "10.0.0.0/8")
+	if ip := ClientIP(r, proxies); ip != "198.51.100.7" {
+		t.Fatalf("ClientIP() = %q, want X-Real-IP", ip)
+	}
+}
+
+func TestClientIPFallsBackToRemoteAddrWithNoHeaders(t *testing.T) {
+	r := &http.Request{RemoteAddr: "10.0.0.1:1234", Header: http.Header{}}
+
+	proxies := trustedProxyCIDR(t, "10.0.0.0/8")
+	if ip := ClientIP(r, proxies); ip != "10.0.0.1" {
+		t.Fatalf("ClientIP() = %q, want RemoteAddr", ip)
+	}
+}
+
+func TestClientIPHandlesRemoteAddrWithoutPort(t *testing.T) {
+	r := &http.Request{RemoteAddr: "203.0.113.5", Header: http.Header{}}
+
+	if ip := ClientIP(r, nil); ip != "203.0.113.5" {
+		t.Fatalf("ClientIP() = %q, want the bare address unchanged", ip)
+	}
+}
+
+func TestIsTrustedRejectsUnparseableIP(t *testing.T) {
+	proxies := trustedProxyCIDR(t, "10.0.0.0/8")
+	if isTrusted("not-an-ip", proxies) {
+		t.Fatalf("isTrusted() = true, want false for an unparseable address")
+	}
+}
@@ -0,0 +1,40 @@
+package middleware
+
+import (
+	"net/http"
+
+	rateLimiter "github.com/go-tron/rate-limiter"
+	"github.com/gin-gonic/gin"
+)
+
+//GinKeyFunc derives the identity a request is rate-limited on from a gin
+//context (IP, user ID, API key, ...).
+type GinKeyFunc func(c *gin.Context) string
+
+//GinConfig configures the gin adapter. See HTTPConfig.Limit.
+type GinConfig struct {
+	Limiter *rateLimiter.RateLimiter
+	KeyFunc GinKeyFunc
+	Limit   int
+}
+
+//Gin returns a gin.HandlerFunc wrapping RateLimiter.Check, setting
+//X-RateLimit-* and Retry-After headers and aborting with 403/429 on
+//ErrorBlock/ErrorWarning.
+func Gin(c GinConfig) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		result, err := c.Limiter.CheckResult(c.KeyFunc(ctx))
+		writeHeaders(ctx.Writer.Header(), result, c.Limit)
+
+		switch err {
+		case nil:
+			ctx.Next()
+		case c.Limiter.BlockError:
+			ctx.AbortWithStatusJSON(http.StatusForbidden, gin.H{"message": err.Error()})
+		case c.Limiter.WarningError:
+			ctx.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{"message": err.Error()})
+		default:
+			ctx.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		}
+	}
+}
@@ -0,0 +1,56 @@
+package middleware
+
+import (
+	"context"
+
+	rateLimiter "github.com/go-tron/rate-limiter"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+//GRPCKeyFunc derives the identity a call is rate-limited on from the call's
+//context (peer IP, an auth token, a metadata header, ...).
+type GRPCKeyFunc func(ctx context.Context) string
+
+//GRPCConfig configures the gRPC interceptors.
+type GRPCConfig struct {
+	Limiter *rateLimiter.RateLimiter
+	KeyFunc GRPCKeyFunc
+}
+
+func (c GRPCConfig) checkErr(ctx context.Context) error {
+	_, err := c.Limiter.CheckResult(c.KeyFunc(ctx))
+	switch err {
+	case nil:
+		return nil
+	case c.Limiter.BlockError:
+		return status.Error(codes.PermissionDenied, err.Error())
+	case c.Limiter.WarningError:
+		return status.Error(codes.ResourceExhausted, err.Error())
+	default:
+		return status.Error(codes.Internal, err.Error())
+	}
+}
+
+//UnaryServerInterceptor rejects calls over the limit with PermissionDenied
+//(block) or ResourceExhausted (warning) gRPC status codes.
+func UnaryServerInterceptor(c GRPCConfig) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if err := c.checkErr(ctx); err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+//StreamServerInterceptor is the streaming counterpart of
+//UnaryServerInterceptor, checked once at stream setup.
+func StreamServerInterceptor(c GRPCConfig) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if err := c.checkErr(ss.Context()); err != nil {
+			return err
+		}
+		return handler(srv, ss)
+	}
+}
@@ -0,0 +1,60 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	rateLimiter "github.com/go-tron/rate-limiter"
+)
+
+//KeyFunc derives the identity a request is rate-limited on (IP, user ID,
+//API key, ...) from the incoming request.
+type KeyFunc func(r *http.Request) string
+
+//HTTPConfig configures the net/http adapter. Limit is reported verbatim as
+//the X-RateLimit-Limit header; since each Strategy keeps its own notion of
+//capacity (BlockTimes, token bucket capacity, sliding-window limit, ...)
+//the adapter can't derive it generically and the caller supplies it.
+type HTTPConfig struct {
+	Limiter *rateLimiter.RateLimiter
+	KeyFunc KeyFunc
+	Limit   int
+}
+
+//HTTP wraps next with RateLimiter.Check, setting X-RateLimit-* and
+//Retry-After headers on every response and translating ErrorBlock/
+//ErrorWarning into 403/429 responses.
+func HTTP(c HTTPConfig, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		result, err := c.Limiter.CheckResult(c.KeyFunc(r))
+		writeHeaders(w.Header(), result, c.Limit)
+
+		switch err {
+		case nil:
+			next.ServeHTTP(w, r)
+		case c.Limiter.BlockError:
+			http.Error(w, err.Error(), http.StatusForbidden)
+		case c.Limiter.WarningError:
+			http.Error(w, err.Error(), http.StatusTooManyRequests)
+		default:
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	})
+}
+
+func writeHeaders(h http.Header, result *rateLimiter.Result, limit int) {
+	if limit > 0 {
+		h.Set("X-RateLimit-Limit", strconv.Itoa(limit))
+	}
+	if result == nil {
+		return
+	}
+	h.Set("X-RateLimit-Remaining", strconv.Itoa(result.Remaining))
+	if !result.ResetAt.IsZero() {
+		h.Set("X-RateLimit-Reset", strconv.FormatInt(result.ResetAt.Unix(), 10))
+	}
+	if result.RetryAfter > 0 {
+		h.Set("Retry-After", strconv.Itoa(int(result.RetryAfter/time.Second)))
+	}
+}
@@ -0,0 +1,179 @@
+package rateLimiter
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"time"
+)
+
+//Op is the action carried by a Message.
+type Op string
+
+const (
+	OpAddWhiteList    Op = "addWhiteList"
+	OpRemoveWhiteList Op = "removeWhiteList"
+	OpAddBlackList    Op = "addBlackList"
+	OpRemoveBlackList Op = "removeBlackList"
+	OpAddBlackListTTL Op = "addBlackListTTL"
+	//OpSync is broadcast by a node on startup to ask peers for their current
+	//whitelist/blacklist, in case this node's own Redis read raced a write.
+	OpSync Op = "sync"
+	//OpSyncResponse answers an OpSync with the responder's full snapshot.
+	OpSyncResponse Op = "syncResponse"
+)
+
+//messageVersion lets future changes to Message evolve the envelope without
+//breaking nodes running the previous version.
+const messageVersion = 1
+
+//Message is the versioned envelope exchanged between nodes, replacing the
+//old ad-hoc "action-id" string that broke for ids containing a dash.
+type Message struct {
+	Version  int       `json:"v"`
+	Op       Op        `json:"op"`
+	ID       string    `json:"id,omitempty"`
+	TTL      int64     `json:"ttl,omitempty"` //milliseconds
+	TS       int64     `json:"ts"`            //unix milliseconds
+	NodeID   string    `json:"node_id"`
+	Snapshot *Snapshot `json:"snapshot,omitempty"`
+}
+
+//Snapshot is the full whitelist/blacklist of a node, sent in reply to an
+//OpSync request so a newly-started node doesn't have to trust its own
+//initial SMembers read alone.
+type Snapshot struct {
+	WhiteList []string `json:"white_list"`
+	BlackList []string `json:"black_list"`
+}
+
+//Publisher delivers a Message to every other node subscribed to channel.
+//Implementations may be backed by Redis pub/sub, NATS, Kafka, etc.
+type Publisher interface {
+	Publish(ctx context.Context, channel string, message []byte) error
+}
+
+//Subscriber delivers messages published to channel to handler until ctx is
+//canceled.
+type Subscriber interface {
+	Subscribe(ctx context.Context, channel string, handler func([]byte)) error
+}
+
+func generateNodeID() string {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return "unknown"
+	}
+	return hex.EncodeToString(b)
+}
+
+func (rl *RateLimiter) publish(op Op, id string, ttl time.Duration) {
+	if rl.Publisher == nil {
+		return
+	}
+	msg := Message{
+		Version: messageVersion,
+		Op:      op,
+		ID:      id,
+		TS:      time.Now().UnixMilli(),
+		NodeID:  rl.nodeID,
+	}
+	if ttl > 0 {
+		msg.TTL = ttl.Milliseconds()
+	}
+	rl.publishMessage(msg)
+}
+
+func (rl *RateLimiter) publishMessage(msg Message) {
+	if rl.Publisher == nil {
+		return
+	}
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return
+	}
+	rl.Publisher.Publish(context.Background(), rl.Name, data)
+}
+
+//subscribe wires rl.Publisher/Subscriber together: it subscribes to this
+//RateLimiter's channel and asks peers for a sync snapshot. The Subscribe
+//goroutine runs until rl.subscribeCtx is canceled by Close.
+func (rl *RateLimiter) subscribe() {
+	if rl.Subscriber != nil {
+		go rl.Subscriber.Subscribe(rl.subscribeCtx, rl.Name, func(data []byte) {
+			rl.Sub(data)
+		})
+	}
+	if rl.Publisher != nil {
+		rl.publishMessage(Message{
+			Version: messageVersion,
+			Op:      OpSync,
+			TS:      time.Now().UnixMilli(),
+			NodeID:  rl.nodeID,
+		})
+	}
+}
+
+//Sub handles one Message received from Publisher/Subscriber (or from
+//whatever transport an external caller wires up). Messages this node
+//published itself are ignored via NodeID so a round trip through the
+//transport can't double-apply a mutation.
+func (rl *RateLimiter) Sub(data []byte) error {
+	var msg Message
+	if err := json.Unmarshal(data, &msg); err != nil {
+		return err
+	}
+	if msg.NodeID == rl.nodeID {
+		return nil
+	}
+
+	switch msg.Op {
+	case OpRemoveWhiteList:
+		return rl.RemoveWhiteList(msg.ID, false)
+	case OpRemoveBlackList:
+		return rl.RemoveBlackList(msg.ID, false)
+	case OpAddWhiteList:
+		return rl.AddWhiteList(msg.ID, false)
+	case OpAddBlackList:
+		return rl.AddBlackList(msg.ID, false)
+	case OpAddBlackListTTL:
+		return rl.syncBlackListTTL(msg.ID)
+	case OpSync:
+		rl.listMu.RLock()
+		snapshot := &Snapshot{WhiteList: rawStrings(rl.whiteList), BlackList: rawStrings(rl.blackList)}
+		rl.listMu.RUnlock()
+		rl.publishMessage(Message{
+			Version:  messageVersion,
+			Op:       OpSyncResponse,
+			TS:       time.Now().UnixMilli(),
+			NodeID:   rl.nodeID,
+			Snapshot: snapshot,
+		})
+		return nil
+	case OpSyncResponse:
+		rl.applySnapshot(msg.Snapshot)
+		return nil
+	default:
+		return nil
+	}
+}
+
+func (rl *RateLimiter) applySnapshot(snap *Snapshot) {
+	if snap == nil {
+		return
+	}
+	rl.listMu.Lock()
+	for _, id := range snap.WhiteList {
+		if indexOfRaw(rl.whiteList, id) == -1 {
+			rl.whiteList = append(rl.whiteList, newListEntry(id))
+		}
+	}
+	for _, id := range snap.BlackList {
+		if indexOfRaw(rl.blackList, id) == -1 {
+			rl.blackList = append(rl.blackList, newListEntry(id))
+		}
+	}
+	rl.listMu.Unlock()
+	rl.updateListSizes()
+}
@@ -0,0 +1,102 @@
+package rateLimiter
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestSubIgnoresMessagesFromItself(t *testing.T) {
+	rl := newTestRateLimiter(t)
+
+	msg := Message{Version: messageVersion, Op: OpAddWhiteList, ID: "1.2.3.4", TS: time.Now().UnixMilli(), NodeID: rl.nodeID}
+	data, err := json.Marshal(msg)
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+	if err := rl.Sub(data); err != nil {
+		t.Fatalf("Sub() error = %v", err)
+	}
+	if ok, _ := rl.IsWhitelisted("1.2.3.4"); ok {
+		t.Fatalf("IsWhitelisted() = true, want false for a message echoed by this node")
+	}
+}
+
+func TestSubDispatchesEachOp(t *testing.T) {
+	rl := newTestRateLimiter(t)
+	peer := "peer-node"
+
+	send := func(t *testing.T, msg Message) {
+		t.Helper()
+		msg.Version = messageVersion
+		msg.TS = time.Now().UnixMilli()
+		msg.NodeID = peer
+		data, err := json.Marshal(msg)
+		if err != nil {
+			t.Fatalf("json.Marshal() error = %v", err)
+		}
+		if err := rl.Sub(data); err != nil {
+			t.Fatalf("Sub() error = %v", err)
+		}
+	}
+
+	send(t, Message{Op: OpAddWhiteList, ID: "1.1.1.1"})
+	if ok, _ := rl.IsWhitelisted("1.1.1.1"); !ok {
+		t.Fatalf("IsWhitelisted() = false after addWhiteList, want true")
+	}
+
+	send(t, Message{Op: OpRemoveWhiteList, ID: "1.1.1.1"})
+	if ok, _ := rl.IsWhitelisted("1.1.1.1"); ok {
+		t.Fatalf("IsWhitelisted() = true after removeWhiteList, want false")
+	}
+
+	send(t, Message{Op: OpAddBlackList, ID: "2.2.2.2"})
+	if ok, _ := rl.IsBlacklisted("2.2.2.2"); !ok {
+		t.Fatalf("IsBlacklisted() = false after addBlackList, want true")
+	}
+
+	send(t, Message{Op: OpRemoveBlackList, ID: "2.2.2.2"})
+	if ok, _ := rl.IsBlacklisted("2.2.2.2"); ok {
+		t.Fatalf("IsBlacklisted() = true after removeBlackList, want false")
+	}
+
+	if _, err := rl.Store.ZAdd(context.Background(), rl.blackListTTLKey, "3.3.3.3", float64(time.Now().Add(time.Hour).UnixMilli())); err != nil {
+		t.Fatalf("ZAdd() error = %v", err)
+	}
+	send(t, Message{Op: OpAddBlackListTTL, ID: "3.3.3.3"})
+	if !rl.isBlacklistedTTL("3.3.3.3") {
+		t.Fatalf("isBlacklistedTTL() = false after addBlackListTTL, want true")
+	}
+
+	send(t, Message{Op: OpSync})
+
+	send(t, Message{Op: OpSyncResponse, Snapshot: &Snapshot{WhiteList: []string{"4.4.4.4"}, BlackList: []string{"5.5.5.5"}}})
+	if ok, _ := rl.IsWhitelisted("4.4.4.4"); !ok {
+		t.Fatalf("IsWhitelisted() = false after syncResponse, want true")
+	}
+	if ok, _ := rl.IsBlacklisted("5.5.5.5"); !ok {
+		t.Fatalf("IsBlacklisted() = false after syncResponse, want true")
+	}
+}
+
+//TestSubRoundTripsIDWithDash is a regression test for the bug this request
+//was written to fix: the old "action-id" string protocol broke for any id
+//containing a dash, since splitting on "-" doesn't know where the action
+//ends and the id begins.
+func TestSubRoundTripsIDWithDash(t *testing.T) {
+	rl := newTestRateLimiter(t)
+	const id = "user-123-abc"
+
+	msg := Message{Version: messageVersion, Op: OpAddWhiteList, ID: id, TS: time.Now().UnixMilli(), NodeID: "peer-node"}
+	data, err := json.Marshal(msg)
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+	if err := rl.Sub(data); err != nil {
+		t.Fatalf("Sub() error = %v", err)
+	}
+	if ok, _ := rl.IsWhitelisted(id); !ok {
+		t.Fatalf("IsWhitelisted(%q) = false, want true", id)
+	}
+}
@@ -4,9 +4,8 @@ import (
 	"context"
 	"github.com/go-tron/base-error"
 	"github.com/go-tron/config"
-	"github.com/go-tron/redis"
-	"github.com/thoas/go-funk"
-	"strings"
+	"github.com/prometheus/client_golang/prometheus"
+	"sync"
 	"time"
 )
 
@@ -25,10 +24,29 @@ type Config struct {
 	BlockDuration time.Duration //0=ever
 	WarningError  *baseError.Error
 	BlockError    *baseError.Error
-	Store         *redis.Redis
+	Store         Backend
 	WhiteList     []string
 	BlackList     []string
-	Pub           func(string, string) error
+	//Publisher broadcasts whitelist/blacklist mutations to other nodes as a
+	//versioned JSON Message. Nil disables cross-node propagation.
+	Publisher Publisher
+	//Subscriber receives Messages broadcast by Publisher on other nodes. Nil
+	//means this node won't apply peers' mutations or answer OpSync requests;
+	//callers can instead feed their own transport's messages into Sub.
+	Subscriber Subscriber
+	//Strategy overrides the default fixed-window counter with a different
+	//rate-limiting algorithm (token bucket, leaky bucket, sliding-window-log).
+	//When nil, Check falls back to the original Store.FrequencyLimit behavior.
+	Strategy Strategy
+	//TTLSweepInterval controls how often the background goroutine purges
+	//expired AddBlackListWithTTL entries from memory. Defaults to one minute.
+	TTLSweepInterval time.Duration
+	//MetricsRegistry, when set, registers Prometheus collectors tracking
+	//check outcomes, list sizes and Store/Strategy latency.
+	MetricsRegistry *prometheus.Registry
+	//EventHook, when set, is called on warning/block transitions and on
+	//whitelist/blacklist mutations.
+	EventHook EventHook
 }
 
 func NewWithConfig(conf *config.Config, c *Config) *RateLimiter {
@@ -62,174 +80,272 @@ func New(c *Config) *RateLimiter {
 	}
 	rl.whiteListKey = rl.Name + "-white"
 	rl.blackListKey = rl.Name + "-black"
+	rl.blackListTTLKey = rl.Name + "-black-ttl"
+	rl.closeSweep = make(chan struct{})
+	rl.subscribeCtx, rl.subscribeCancel = context.WithCancel(context.Background())
+	rl.nodeID = generateNodeID()
+	if c.MetricsRegistry != nil {
+		rl.metrics = newMetrics(c.MetricsRegistry, rl.Name)
+	}
 	for _, val := range c.WhiteList {
-		rl.whiteList = append(rl.whiteList, val)
+		rl.whiteList = append(rl.whiteList, newListEntry(val))
 	}
 	for _, val := range c.BlackList {
-		rl.blackList = append(rl.blackList, val)
+		rl.blackList = append(rl.blackList, newListEntry(val))
 	}
-	whiteList, err := rl.Store.SMembers(context.Background(), rl.whiteListKey).Result()
+	whiteList, err := rl.Store.SMembers(context.Background(), rl.whiteListKey)
 	if err == nil {
 		for _, val := range whiteList {
-			if !funk.ContainsString(rl.whiteList, val) {
-				rl.whiteList = append(rl.whiteList, val)
+			if indexOfRaw(rl.whiteList, val) == -1 {
+				rl.whiteList = append(rl.whiteList, newListEntry(val))
 			}
 		}
 	}
-	blackList, err := rl.Store.SMembers(context.Background(), rl.blackListKey).Result()
+	blackList, err := rl.Store.SMembers(context.Background(), rl.blackListKey)
 	if err == nil {
 		for _, val := range blackList {
-			if !funk.ContainsString(rl.blackList, val) {
-				rl.blackList = append(rl.blackList, val)
+			if indexOfRaw(rl.blackList, val) == -1 {
+				rl.blackList = append(rl.blackList, newListEntry(val))
 			}
 		}
 	}
+	rl.hydrateBlackListTTL()
+	go rl.startTTLSweeper()
+	rl.subscribe()
+	rl.updateListSizes()
 	return &rl
 }
 
 type RateLimiter struct {
 	*Config
-	whiteList    []string
-	blackList    []string
-	whiteListKey string
-	blackListKey string
+	listMu          sync.RWMutex
+	whiteList       []listEntry
+	blackList       []listEntry
+	whiteListKey    string
+	blackListKey    string
+	blackListTTLKey string
+	blackListTTL    []ttlEntry
+	ttlMu           sync.Mutex
+	closeSweep      chan struct{}
+	closeOnce       sync.Once
+	subscribeCtx    context.Context
+	subscribeCancel context.CancelFunc
+	nodeID          string
+	metrics         *metrics
 }
 
 func (rl *RateLimiter) Check(id string) (int, error) {
-	if funk.Contains(rl.whiteList, id) {
-		return 0, nil
+	_, times, err := rl.check(id)
+	return times, err
+}
+
+//CheckResult behaves like Check but returns a Result carrying Remaining,
+//RetryAfter and ResetAt, which middleware can use to emit X-RateLimit-*
+//headers. It is the only entry point that reports a meaningful Result when
+//Config.Strategy is set, since token-bucket/leaky-bucket/sliding-window-log
+//don't have a "times" counter to hand back through Check's int.
+func (rl *RateLimiter) CheckResult(id string) (*Result, error) {
+	result, _, err := rl.check(id)
+	return result, err
+}
+
+//IsWhitelisted reports whether id matches the whitelist, and if so whether
+//it matched a literal entry or a CIDR range.
+func (rl *RateLimiter) IsWhitelisted(id string) (bool, MatchReason) {
+	rl.listMu.RLock()
+	defer rl.listMu.RUnlock()
+	return matchList(rl.whiteList, id)
+}
+
+//IsBlacklisted reports whether id matches the blacklist, and if so whether
+//it matched a literal entry or a CIDR range.
+func (rl *RateLimiter) IsBlacklisted(id string) (bool, MatchReason) {
+	rl.listMu.RLock()
+	defer rl.listMu.RUnlock()
+	return matchList(rl.blackList, id)
+}
+
+func (rl *RateLimiter) check(id string) (*Result, int, error) {
+	if ok, _ := rl.IsWhitelisted(id); ok {
+		rl.recordCheck("whitelisted", 0)
+		return &Result{Allowed: true}, 0, nil
+	}
+
+	if ok, _ := rl.IsBlacklisted(id); ok {
+		rl.recordCheck("blocked", 0)
+		rl.fireEvent(EventBlocked, id)
+		return &Result{Allowed: false}, 0, rl.BlockError
+	}
+
+	if rl.isBlacklistedTTL(id) {
+		rl.recordCheck("blocked", 0)
+		rl.fireEvent(EventBlocked, id)
+		return &Result{Allowed: false}, 0, rl.BlockError
 	}
 
-	if funk.Contains(rl.blackList, id) {
-		return 0, rl.BlockError
+	if rl.Strategy != nil {
+		started := time.Now()
+		result, err := rl.Strategy.Allow(context.Background(), rl.Store, rl.Name+":"+id)
+		if err != nil {
+			return nil, 0, err
+		}
+		if !result.Allowed {
+			rl.recordCheck("blocked", time.Since(started))
+			rl.fireEvent(EventBlocked, id)
+			return result, 0, rl.BlockError
+		}
+		rl.recordCheck("allowed", time.Since(started))
+		return result, 0, nil
 	}
 
-	times, err := rl.Store.FrequencyLimit(context.Background(), rl.Name+":"+id, 0, rl.Duration)
+	started := time.Now()
+	key := rl.Name + ":" + id
+	times, err := rl.Store.FrequencyLimit(context.Background(), key, 0, rl.Duration)
+	duration := time.Since(started)
 	if err != nil {
-		return 0, err
+		return nil, 0, err
+	}
+
+	resetAt := time.Now().Add(rl.Duration)
+	if ttl, err := rl.Store.TTL(context.Background(), key); err == nil && ttl > 0 {
+		resetAt = time.Now().Add(ttl)
+	}
+	result := &Result{Allowed: true, ResetAt: resetAt}
+	if rl.BlockTimes > 0 {
+		if remaining := rl.BlockTimes - times; remaining > 0 {
+			result.Remaining = remaining
+		}
 	}
 
 	if rl.BlockTimes > 0 && times >= rl.BlockTimes {
 		if rl.BlockDuration == 0 {
 			rl.AddBlackList(id, true)
 		} else {
-			rl.Store.Expire(context.Background(), rl.Name+":"+id, rl.BlockDuration)
+			rl.Store.Expire(context.Background(), key, rl.BlockDuration)
+			result.RetryAfter = rl.BlockDuration
 		}
-		return times, rl.BlockError
+		result.Allowed = false
+		rl.recordCheck("blocked", duration)
+		rl.fireEvent(EventBlocked, id)
+		return result, times, rl.BlockError
 	} else if rl.WarningTimes > 0 && times >= rl.WarningTimes {
-		return times, rl.WarningError
+		rl.recordCheck("warned", duration)
+		if times == rl.WarningTimes {
+			rl.fireEvent(EventFirstWarning, id)
+		}
+		return result, times, rl.WarningError
 	}
 
-	return times, nil
+	rl.recordCheck("allowed", duration)
+	return result, times, nil
 }
 
 func (rl *RateLimiter) CheckReset(id string) error {
-	_, err := rl.Store.Del(context.Background(), rl.Name+":"+id).Result()
+	_, err := rl.Store.Del(context.Background(), rl.Name+":"+id)
 	return err
 }
 
-func (rl *RateLimiter) Sub(message string) error {
-	str := strings.Split(message, "-")
-	if len(str) != 2 {
-		return nil
-	}
-	switch str[0] {
-	case "removeWhiteList":
-		return rl.RemoveWhiteList(str[1], false)
-	case "removeBlackList":
-		return rl.RemoveBlackList(str[1], false)
-	case "addWhiteList":
-		return rl.AddWhiteList(str[1], false)
-	case "addBlackList":
-		return rl.AddBlackList(str[1], false)
-	default:
-		return nil
-	}
-}
-
 func (rl *RateLimiter) RemoveWhiteList(id string, pub bool) error {
-	_, err := rl.Store.SRem(context.Background(), rl.whiteListKey, id).Result()
+	_, err := rl.Store.SRem(context.Background(), rl.whiteListKey, id)
 	if err != nil {
 		return err
 	}
-	idx := funk.IndexOfString(rl.whiteList, id)
+	rl.listMu.Lock()
+	idx := indexOfRaw(rl.whiteList, id)
 	if idx != -1 {
 		rl.whiteList = append(rl.whiteList[:idx], rl.whiteList[idx+1:]...)
 	}
-	if pub && rl.Pub != nil {
-		rl.Pub(rl.Name, "removeWhiteList-"+id)
+	rl.listMu.Unlock()
+	rl.updateListSizes()
+	rl.fireEvent(EventListMutation, id)
+	if pub {
+		rl.publish(OpRemoveWhiteList, id, 0)
 	}
 	return nil
 }
 
 func (rl *RateLimiter) RemoveBlackList(id string, pub bool) error {
-	_, err := rl.Store.SRem(context.Background(), rl.blackListKey, id).Result()
+	_, err := rl.Store.SRem(context.Background(), rl.blackListKey, id)
 	if err != nil {
 		return err
 	}
-	idx := funk.IndexOfString(rl.blackList, id)
+	rl.listMu.Lock()
+	idx := indexOfRaw(rl.blackList, id)
 	if idx != -1 {
 		rl.blackList = append(rl.blackList[:idx], rl.blackList[idx+1:]...)
 	}
-	if pub && rl.Pub != nil {
-		rl.Pub(rl.Name, "removeBlackList-"+id)
+	rl.listMu.Unlock()
+	rl.updateListSizes()
+	rl.fireEvent(EventListMutation, id)
+	if pub {
+		rl.publish(OpRemoveBlackList, id, 0)
 	}
 	return rl.CheckReset(id)
 }
 
 func (rl *RateLimiter) AddWhiteList(id string, pub bool) error {
-	_, err := rl.Store.SAdd(context.Background(), rl.whiteListKey, id).Result()
+	_, err := rl.Store.SAdd(context.Background(), rl.whiteListKey, id)
 	if err != nil {
 		return err
 	}
 
-	idx := funk.IndexOfString(rl.whiteList, id)
+	rl.listMu.Lock()
+	idx := indexOfRaw(rl.whiteList, id)
 	if idx != -1 {
+		rl.listMu.Unlock()
 		return ErrorWhiteListExists
 	}
-	rl.whiteList = append(rl.whiteList, id)
-	if pub && rl.Pub != nil {
-		rl.Pub(rl.Name, "addWhiteList-"+id)
+	rl.whiteList = append(rl.whiteList, newListEntry(id))
+	rl.listMu.Unlock()
+	rl.updateListSizes()
+	rl.fireEvent(EventListMutation, id)
+	if pub {
+		rl.publish(OpAddWhiteList, id, 0)
 	}
 	return nil
 }
 
 func (rl *RateLimiter) AddBlackList(id string, pub bool) error {
-	_, err := rl.Store.SAdd(context.Background(), rl.blackListKey, id).Result()
+	_, err := rl.Store.SAdd(context.Background(), rl.blackListKey, id)
 	if err != nil {
 		return err
 	}
-	idx := funk.IndexOfString(rl.blackList, id)
+	rl.listMu.Lock()
+	idx := indexOfRaw(rl.blackList, id)
 	if idx != -1 {
+		rl.listMu.Unlock()
 		return ErrorBlackListExists
 	}
-	rl.blackList = append(rl.blackList, id)
-	if pub && rl.Pub != nil {
-		rl.Pub(rl.Name, "addBlackList-"+id)
+	rl.blackList = append(rl.blackList, newListEntry(id))
+	rl.listMu.Unlock()
+	rl.updateListSizes()
+	rl.fireEvent(EventListMutation, id)
+	if pub {
+		rl.publish(OpAddBlackList, id, 0)
 	}
 	return nil
 }
 
 func (rl *RateLimiter) GetWhiteList(id interface{}) ([]string, error) {
 	if id != nil {
-		has := funk.ContainsString(rl.whiteList, id.(string))
-		if has {
+		if ok, _ := rl.IsWhitelisted(id.(string)); ok {
 			return []string{id.(string)}, nil
-		} else {
-			return nil, nil
 		}
+		return nil, nil
 	}
-	return rl.whiteList, nil
+	rl.listMu.RLock()
+	defer rl.listMu.RUnlock()
+	return rawStrings(rl.whiteList), nil
 }
 
 func (rl *RateLimiter) GetBlackList(id interface{}) ([]string, error) {
 	if id != nil {
-		has := funk.ContainsString(rl.blackList, id.(string))
-		if has {
+		if ok, _ := rl.IsBlacklisted(id.(string)); ok {
 			return []string{id.(string)}, nil
-		} else {
-			return nil, nil
 		}
+		return nil, nil
 	}
-	return rl.blackList, nil
+	rl.listMu.RLock()
+	defer rl.listMu.RUnlock()
+	return rawStrings(rl.blackList), nil
 }
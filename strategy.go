@@ -0,0 +1,224 @@
+package rateLimiter
+
+import (
+	"context"
+	"time"
+)
+
+//Result is the outcome of a Strategy check, rich enough for callers to emit
+//X-RateLimit-* headers without re-deriving them from Check's plain (int, error).
+type Result struct {
+	Allowed    bool
+	Remaining  int
+	RetryAfter time.Duration
+	ResetAt    time.Time
+}
+
+//Strategy decides whether a call identified by key is allowed to proceed.
+//Implementations must be safe for concurrent use and atomic against Backend,
+//typically via EvalScript so the read-modify-write cannot race across nodes.
+type Strategy interface {
+	Allow(ctx context.Context, store Backend, key string) (*Result, error)
+}
+
+//tokenBucketScript refills tokens based on elapsed time since the last call,
+//then deducts one token if available. KEYS[1] holds {tokens, last_refill_ts}
+//as a Redis hash. ARGV: capacity, rate(tokens/sec), now(unix ms).
+const tokenBucketScript = `
+local key = KEYS[1]
+local capacity = tonumber(ARGV[1])
+local rate = tonumber(ARGV[2])
+local now = tonumber(ARGV[3])
+
+local tokens = capacity
+local last = now
+
+local data = redis.call('HMGET', key, 'tokens', 'last_refill_ts')
+if data[1] and data[2] then
+	tokens = tonumber(data[1])
+	last = tonumber(data[2])
+	local elapsed = math.max(0, now - last) / 1000
+	tokens = math.min(capacity, tokens + elapsed * rate)
+end
+
+local allowed = 0
+local retryAfter = 0
+if tokens >= 1 then
+	allowed = 1
+	tokens = tokens - 1
+else
+	retryAfter = math.ceil((1 - tokens) / rate * 1000)
+end
+
+redis.call('HMSET', key, 'tokens', tokens, 'last_refill_ts', now)
+redis.call('PEXPIRE', key, math.ceil(capacity / rate * 1000) + 1000)
+
+return {allowed, math.floor(tokens), retryAfter}
+`
+
+//TokenBucket allows Capacity calls up to the configured burst size, refilling
+//at Rate tokens per second.
+type TokenBucket struct {
+	Capacity int
+	Rate     float64
+}
+
+func NewTokenBucket(capacity int, rate float64) *TokenBucket {
+	return &TokenBucket{Capacity: capacity, Rate: rate}
+}
+
+func (s *TokenBucket) Allow(ctx context.Context, store Backend, key string) (*Result, error) {
+	now := time.Now()
+	res, err := store.EvalScript(ctx, tokenBucketScript, []string{key}, s.Capacity, s.Rate, now.UnixMilli())
+	if err != nil {
+		return nil, err
+	}
+	vals := res.([]interface{})
+	allowed := vals[0].(int64) == 1
+	remaining := int(vals[1].(int64))
+	retryAfter := time.Duration(vals[2].(int64)) * time.Millisecond
+	//ResetAt is when the bucket refills back to full, derived from how many
+	//tokens this call actually found remaining rather than a worst-case
+	//"now + capacity/rate" that ignores the current fill level.
+	untilFull := time.Duration(float64(s.Capacity-remaining) / s.Rate * float64(time.Second))
+	return &Result{
+		Allowed:    allowed,
+		Remaining:  remaining,
+		RetryAfter: retryAfter,
+		ResetAt:    now.Add(untilFull),
+	}, nil
+}
+
+//leakyBucketScript models a queue that leaks at a fixed rate: each call adds
+//one request to the queue unless it is already full. KEYS[1] holds
+//{level, last_leak_ts}. ARGV: capacity, rate(leaks/sec), now(unix ms).
+const leakyBucketScript = `
+local key = KEYS[1]
+local capacity = tonumber(ARGV[1])
+local rate = tonumber(ARGV[2])
+local now = tonumber(ARGV[3])
+
+local level = 0
+local last = now
+
+local data = redis.call('HMGET', key, 'level', 'last_leak_ts')
+if data[1] and data[2] then
+	level = tonumber(data[1])
+	last = tonumber(data[2])
+	local elapsed = math.max(0, now - last) / 1000
+	level = math.max(0, level - elapsed * rate)
+end
+
+local allowed = 0
+local retryAfter = 0
+if level < capacity then
+	allowed = 1
+	level = level + 1
+else
+	retryAfter = math.ceil((level - capacity + 1) / rate * 1000)
+end
+
+redis.call('HMSET', key, 'level', level, 'last_leak_ts', now)
+redis.call('PEXPIRE', key, math.ceil(capacity / rate * 1000) + 1000)
+
+return {allowed, math.floor(capacity - level), retryAfter}
+`
+
+//LeakyBucket allows Capacity queued requests to drain at Rate per second,
+//rejecting once the queue is full.
+type LeakyBucket struct {
+	Capacity int
+	Rate     float64
+}
+
+func NewLeakyBucket(capacity int, rate float64) *LeakyBucket {
+	return &LeakyBucket{Capacity: capacity, Rate: rate}
+}
+
+func (s *LeakyBucket) Allow(ctx context.Context, store Backend, key string) (*Result, error) {
+	now := time.Now()
+	res, err := store.EvalScript(ctx, leakyBucketScript, []string{key}, s.Capacity, s.Rate, now.UnixMilli())
+	if err != nil {
+		return nil, err
+	}
+	vals := res.([]interface{})
+	allowed := vals[0].(int64) == 1
+	remaining := int(vals[1].(int64))
+	retryAfter := time.Duration(vals[2].(int64)) * time.Millisecond
+	//ResetAt is when the queue fully drains, derived from the current queue
+	//level (capacity-remaining) rather than a worst-case "now + capacity/rate"
+	//that ignores how much of the queue this call actually found occupied.
+	level := float64(s.Capacity - remaining)
+	untilDrained := time.Duration(level / s.Rate * float64(time.Second))
+	return &Result{
+		Allowed:    allowed,
+		Remaining:  remaining,
+		RetryAfter: retryAfter,
+		ResetAt:    now.Add(untilDrained),
+	}, nil
+}
+
+//slidingWindowLogScript trims entries older than the window, counts what
+//remains and adds the current call if under the limit. KEYS[1] is a ZSET
+//scored by call timestamp. ARGV: limit, window(ms), now(unix ms). The
+//oldest surviving entry's expiry (its score plus window) is returned so the
+//caller can report when the window actually clears rather than assuming a
+//fresh "now + window".
+const slidingWindowLogScript = `
+local key = KEYS[1]
+local limit = tonumber(ARGV[1])
+local window = tonumber(ARGV[2])
+local now = tonumber(ARGV[3])
+
+redis.call('ZREMRANGEBYSCORE', key, 0, now - window)
+local count = redis.call('ZCARD', key)
+
+local allowed = 0
+if count < limit then
+	allowed = 1
+	redis.call('ZADD', key, now, now .. '-' .. math.random(1000000))
+	count = count + 1
+end
+redis.call('PEXPIRE', key, window)
+
+local resetAt = now + window
+local oldest = redis.call('ZRANGE', key, 0, 0, 'WITHSCORES')
+if oldest[2] then
+	resetAt = tonumber(oldest[2]) + window
+end
+
+return {allowed, math.max(0, limit - count), resetAt}
+`
+
+//SlidingWindowLog allows Limit calls in any trailing Window, tracking each
+//call's timestamp rather than a per-window counter so bursts at a window
+//boundary can't double the effective rate.
+type SlidingWindowLog struct {
+	Limit  int
+	Window time.Duration
+}
+
+func NewSlidingWindowLog(limit int, window time.Duration) *SlidingWindowLog {
+	return &SlidingWindowLog{Limit: limit, Window: window}
+}
+
+func (s *SlidingWindowLog) Allow(ctx context.Context, store Backend, key string) (*Result, error) {
+	now := time.Now()
+	res, err := store.EvalScript(ctx, slidingWindowLogScript, []string{key}, s.Limit, s.Window.Milliseconds(), now.UnixMilli())
+	if err != nil {
+		return nil, err
+	}
+	vals := res.([]interface{})
+	allowed := vals[0].(int64) == 1
+	remaining := int(vals[1].(int64))
+	resetAt := time.UnixMilli(vals[2].(int64))
+	result := &Result{
+		Allowed:   allowed,
+		Remaining: remaining,
+		ResetAt:   resetAt,
+	}
+	if !allowed {
+		result.RetryAfter = resetAt.Sub(now)
+	}
+	return result, nil
+}
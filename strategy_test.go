@@ -0,0 +1,230 @@
+package rateLimiter
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestTokenBucketAllowsUpToCapacityThenDenies(t *testing.T) {
+	store := NewInMemoryBackend()
+	defer store.Close()
+	ctx := context.Background()
+	s := NewTokenBucket(2, 1)
+
+	for i := 1; i <= 2; i++ {
+		res, err := s.Allow(ctx, store, "key")
+		if err != nil {
+			t.Fatalf("Allow() call %d error = %v", i, err)
+		}
+		if !res.Allowed {
+			t.Fatalf("Allow() call %d = denied, want allowed", i)
+		}
+	}
+
+	res, err := s.Allow(ctx, store, "key")
+	if err != nil {
+		t.Fatalf("Allow() error = %v", err)
+	}
+	if res.Allowed {
+		t.Fatalf("Allow() after exhausting capacity = allowed, want denied")
+	}
+	if res.RetryAfter <= 0 {
+		t.Fatalf("RetryAfter = %v, want > 0", res.RetryAfter)
+	}
+}
+
+func TestTokenBucketRefillsAfterElapsedTime(t *testing.T) {
+	store := NewInMemoryBackend()
+	defer store.Close()
+	ctx := context.Background()
+	s := NewTokenBucket(1, 20) // 20 tokens/sec refill
+
+	if res, err := s.Allow(ctx, store, "key"); err != nil || !res.Allowed {
+		t.Fatalf("Allow() first call = %v, %v, want allowed, nil", res, err)
+	}
+	if res, _ := s.Allow(ctx, store, "key"); res.Allowed {
+		t.Fatalf("Allow() immediately after exhausting capacity = allowed, want denied")
+	}
+
+	time.Sleep(100 * time.Millisecond) // refills ~2 tokens at 20/sec
+
+	res, err := s.Allow(ctx, store, "key")
+	if err != nil {
+		t.Fatalf("Allow() after refill error = %v", err)
+	}
+	if !res.Allowed {
+		t.Fatalf("Allow() after refill = denied, want allowed")
+	}
+}
+
+//TestTokenBucketResetAtReflectsCurrentFillLevel guards against ResetAt
+//being computed as a worst-case "now + capacity/rate" that ignores how many
+//tokens this call actually found in the bucket.
+func TestTokenBucketResetAtReflectsCurrentFillLevel(t *testing.T) {
+	store := NewInMemoryBackend()
+	defer store.Close()
+	ctx := context.Background()
+	s := NewTokenBucket(5, 1) // worst case (empty bucket) would refill in 5s
+
+	res, err := s.Allow(ctx, store, "key")
+	if err != nil {
+		t.Fatalf("Allow() error = %v", err)
+	}
+	if !res.Allowed || res.Remaining != 4 {
+		t.Fatalf("Allow() = %+v, want allowed with 4 remaining", res)
+	}
+	if until := time.Until(res.ResetAt); until <= 0 || until > 2*time.Second {
+		t.Fatalf("ResetAt is %v out, want ~1s (one token short of full), not the ~5s worst case", until)
+	}
+}
+
+func TestTokenBucketRetryAfterMath(t *testing.T) {
+	store := NewInMemoryBackend()
+	defer store.Close()
+	ctx := context.Background()
+	s := NewTokenBucket(1, 1) // 1 token/sec refill
+
+	if res, err := s.Allow(ctx, store, "key"); err != nil || !res.Allowed {
+		t.Fatalf("Allow() first call = %v, %v, want allowed, nil", res, err)
+	}
+
+	res, err := s.Allow(ctx, store, "key")
+	if err != nil {
+		t.Fatalf("Allow() error = %v", err)
+	}
+	if res.Allowed {
+		t.Fatalf("Allow() immediately after exhausting capacity = allowed, want denied")
+	}
+	if res.RetryAfter <= 900*time.Millisecond || res.RetryAfter > time.Second {
+		t.Fatalf("RetryAfter = %v, want ~1s", res.RetryAfter)
+	}
+}
+
+func TestLeakyBucketAllowsUpToCapacityThenDenies(t *testing.T) {
+	store := NewInMemoryBackend()
+	defer store.Close()
+	ctx := context.Background()
+	s := NewLeakyBucket(1, 1)
+
+	if res, err := s.Allow(ctx, store, "key"); err != nil || !res.Allowed {
+		t.Fatalf("Allow() first call = %v, %v, want allowed, nil", res, err)
+	}
+
+	res, err := s.Allow(ctx, store, "key")
+	if err != nil {
+		t.Fatalf("Allow() error = %v", err)
+	}
+	if res.Allowed {
+		t.Fatalf("Allow() with a full queue = allowed, want denied")
+	}
+	if res.RetryAfter <= 900*time.Millisecond || res.RetryAfter > time.Second {
+		t.Fatalf("RetryAfter = %v, want ~1s", res.RetryAfter)
+	}
+}
+
+func TestLeakyBucketDrainsAfterElapsedTime(t *testing.T) {
+	store := NewInMemoryBackend()
+	defer store.Close()
+	ctx := context.Background()
+	s := NewLeakyBucket(1, 20) // 20/sec leak rate
+
+	if res, err := s.Allow(ctx, store, "key"); err != nil || !res.Allowed {
+		t.Fatalf("Allow() first call = %v, %v, want allowed, nil", res, err)
+	}
+	if res, _ := s.Allow(ctx, store, "key"); res.Allowed {
+		t.Fatalf("Allow() with a full queue = allowed, want denied")
+	}
+
+	time.Sleep(100 * time.Millisecond) // drains ~2 slots at 20/sec
+
+	res, err := s.Allow(ctx, store, "key")
+	if err != nil {
+		t.Fatalf("Allow() after drain error = %v", err)
+	}
+	if !res.Allowed {
+		t.Fatalf("Allow() after drain = denied, want allowed")
+	}
+}
+
+func TestSlidingWindowLogAllowsUpToLimitThenDenies(t *testing.T) {
+	store := NewInMemoryBackend()
+	defer store.Close()
+	ctx := context.Background()
+	window := 100 * time.Millisecond
+	s := NewSlidingWindowLog(2, window)
+
+	for i := 1; i <= 2; i++ {
+		res, err := s.Allow(ctx, store, "key")
+		if err != nil {
+			t.Fatalf("Allow() call %d error = %v", i, err)
+		}
+		if !res.Allowed {
+			t.Fatalf("Allow() call %d = denied, want allowed", i)
+		}
+	}
+
+	res, err := s.Allow(ctx, store, "key")
+	if err != nil {
+		t.Fatalf("Allow() error = %v", err)
+	}
+	if res.Allowed {
+		t.Fatalf("Allow() over the limit = allowed, want denied")
+	}
+	if res.RetryAfter <= 0 || res.RetryAfter > window {
+		t.Fatalf("RetryAfter = %v, want (0, %v]", res.RetryAfter, window)
+	}
+}
+
+//TestSlidingWindowLogResetAtTracksOldestEntry guards against ResetAt
+//drifting forward on every call: a call partway through the window must
+//still report the window closing when the oldest entry ages out, not
+//"now + window" from whenever this particular call happened to land.
+func TestSlidingWindowLogResetAtTracksOldestEntry(t *testing.T) {
+	store := NewInMemoryBackend()
+	defer store.Close()
+	ctx := context.Background()
+	window := 200 * time.Millisecond
+	s := NewSlidingWindowLog(2, window)
+
+	first, err := s.Allow(ctx, store, "key")
+	if err != nil {
+		t.Fatalf("Allow() error = %v", err)
+	}
+	wantResetAt := first.ResetAt
+
+	time.Sleep(100 * time.Millisecond)
+
+	second, err := s.Allow(ctx, store, "key")
+	if err != nil {
+		t.Fatalf("Allow() error = %v", err)
+	}
+	if diff := second.ResetAt.Sub(wantResetAt); diff < -5*time.Millisecond || diff > 5*time.Millisecond {
+		t.Fatalf("ResetAt moved by %v after a later call, want it pinned to the oldest entry's expiry", diff)
+	}
+}
+
+func TestSlidingWindowLogAllowsAgainAfterWindowElapses(t *testing.T) {
+	store := NewInMemoryBackend()
+	defer store.Close()
+	ctx := context.Background()
+	window := 50 * time.Millisecond
+	s := NewSlidingWindowLog(1, window)
+
+	if res, err := s.Allow(ctx, store, "key"); err != nil || !res.Allowed {
+		t.Fatalf("Allow() first call = %v, %v, want allowed, nil", res, err)
+	}
+	if res, _ := s.Allow(ctx, store, "key"); res.Allowed {
+		t.Fatalf("Allow() over the limit = allowed, want denied")
+	}
+
+	time.Sleep(2 * window)
+
+	res, err := s.Allow(ctx, store, "key")
+	if err != nil {
+		t.Fatalf("Allow() after window elapsed error = %v", err)
+	}
+	if !res.Allowed {
+		t.Fatalf("Allow() after window elapsed = denied, want allowed")
+	}
+}